@@ -0,0 +1,334 @@
+package backtest
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"os"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+)
+
+var ErrNoCapital = errors.New("the position must be provided some tokens")
+
+// A Strategy decides the range of the simulated position as the events are replayed
+type Strategy interface {
+	/**
+	 * Rebalance is called after each event is replayed, with the state after the event
+	 * @returns The range to move the position to, and whether to move it
+	 */
+	Rebalance(snapshot *Snapshot) (tickLower, tickUpper int, rebalance bool)
+}
+
+// StrategyFunc adapts a function to the Strategy interface
+type StrategyFunc func(snapshot *Snapshot) (tickLower, tickUpper int, rebalance bool)
+
+func (f StrategyFunc) Rebalance(snapshot *Snapshot) (int, int, bool) {
+	return f(snapshot)
+}
+
+/**
+ * Recenter returns a strategy that moves the position to a range of about width ticks centered on the current tick,
+ * each time the price moves out of its range
+ * @param width the number of ticks between the lower and upper tick of the ranges, rounded to the tick spacing
+ */
+func Recenter(width int) Strategy {
+	return StrategyFunc(func(snapshot *Snapshot) (int, int, bool) {
+		current := snapshot.Pool.CurrentTick
+		if snapshot.Position.TickLower <= current && current < snapshot.Position.TickUpper {
+			return 0, 0, false
+		}
+		tickSpacing := constants.TickSpacings[snapshot.Pool.Fee]
+		tickLower := entities.NearestUsableTick(current-width/2, tickSpacing)
+		tickUpper := entities.NearestUsableTick(current+width/2, tickSpacing)
+		if tickUpper <= tickLower {
+			tickUpper = tickLower + tickSpacing
+		}
+		return tickLower, tickUpper, true
+	})
+}
+
+type Config struct {
+	TickLower int      // The lower tick of the initial range
+	TickUpper int      // The upper tick of the initial range
+	Amount0   *big.Int // The token0 provided, what the initial range does not take is kept aside
+	Amount1   *big.Int // The token1 provided, what the initial range does not take is kept aside
+	Strategy  Strategy // Optional, the position stays in its initial range without one
+}
+
+// The state of the simulated position after an event
+type Snapshot struct {
+	Event      *Event // The event replayed, nil before the first event
+	Pool       *entities.Pool
+	Position   *entities.Position   // The position, minted in Pool
+	FeeL       *big.Int             // The reinvestment liquidity the fees earned by the position add up to
+	Fee0       *big.Int             // The token0 FeeL can be burned for at the pool price
+	Fee1       *big.Int             // The token1 FeeL can be burned for at the pool price
+	Idle0      *big.Int             // The token0 kept aside, not in the position
+	Idle1      *big.Int             // The token1 kept aside, not in the position
+	Value      *core.CurrencyAmount // The value of the position, its fees and the tokens kept aside, in token1 at the pool price
+	HoldValue  *core.CurrencyAmount // The value of the tokens provided had they been held instead, in token1 at the pool price
+	Rebalanced bool                 // Whether the strategy moved the position after the event
+}
+
+type Result struct {
+	Snapshots  []*Snapshot // The state before the first event, then after each event
+	Rebalances int
+}
+
+/**
+ * A Backtester replays the events of a pool with a simulated position minted in it. Swaps are replayed as exact input
+ * swaps through the pool math, their fees being reinvested as in the pool: every unit of base liquidity in range and
+ * of reinvestment liquidity earns the same share of the reinvestment liquidity a swap adds.
+ */
+type Backtester struct {
+	config    *Config
+	pool      *entities.Pool
+	position  *entities.Position
+	feeL      *big.Int
+	idle0     *big.Int
+	idle1     *big.Int
+	snapshots []*Snapshot
+}
+
+/**
+ * Constructs a backtester, minting the position in its initial range
+ * @param pool the state of the pool before the first event
+ * @param config the position and strategy to simulate
+ */
+func NewBacktester(pool *entities.Pool, config *Config) (*Backtester, error) {
+	idle0, idle1 := new(big.Int), new(big.Int)
+	if config.Amount0 != nil {
+		idle0.Set(config.Amount0)
+	}
+	if config.Amount1 != nil {
+		idle1.Set(config.Amount1)
+	}
+	if idle0.Sign() <= 0 && idle1.Sign() <= 0 {
+		return nil, ErrNoCapital
+	}
+	b := &Backtester{config: config, pool: pool, feeL: big.NewInt(0), idle0: idle0, idle1: idle1}
+	if err := b.mint(config.TickLower, config.TickUpper); err != nil {
+		return nil, err
+	}
+	snapshot, err := b.snapshot(nil)
+	if err != nil {
+		return nil, err
+	}
+	b.snapshots = append(b.snapshots, snapshot)
+	return b, nil
+}
+
+// Apply replays an event, and lets the strategy rebalance the position after it
+func (b *Backtester) Apply(event *Event) (*Snapshot, error) {
+	change, err := event.Change()
+	if err != nil {
+		return nil, err
+	}
+	if err := change.Validate(); err != nil {
+		return nil, err
+	}
+	// swaps are replayed here rather than by the store, to credit the position with its share of the fees
+	if event.Type == EventSwap {
+		err = b.swap(event)
+	} else {
+		b.pool, err = change.Apply(b.pool)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.position, err = entities.NewPosition(b.pool, b.position.Liquidity, b.position.TickLower, b.position.TickUpper)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := b.snapshot(event)
+	if err != nil {
+		return nil, err
+	}
+	if b.config.Strategy != nil {
+		tickLower, tickUpper, rebalance := b.config.Strategy.Rebalance(snapshot)
+		if rebalance && (tickLower != b.position.TickLower || tickUpper != b.position.TickUpper) {
+			if err := b.burn(); err != nil {
+				return nil, err
+			}
+			if err := b.mint(tickLower, tickUpper); err != nil {
+				return nil, err
+			}
+			if snapshot, err = b.snapshot(event); err != nil {
+				return nil, err
+			}
+			snapshot.Rebalanced = true
+		}
+	}
+	b.snapshots = append(b.snapshots, snapshot)
+	return snapshot, nil
+}
+
+// Result returns the snapshots taken so far
+func (b *Backtester) Result() *Result {
+	result := &Result{Snapshots: b.snapshots}
+	for _, snapshot := range b.snapshots {
+		if snapshot.Rebalanced {
+			result.Rebalances++
+		}
+	}
+	return result
+}
+
+// Run replays all the events of the reader
+func Run(pool *entities.Pool, reader *EventReader, config *Config) (*Result, error) {
+	b, err := NewBacktester(pool, config)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return b.Result(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if _, err := b.Apply(event); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// RunFile replays all the events of a JSONL file
+func RunFile(pool *entities.Pool, path string, config *Config) (*Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Run(pool, NewEventReader(f), config)
+}
+
+// swap replays a swap, crediting the position with its share of the fees of each segment of the swap
+func (b *Backtester) swap(event *Event) error {
+	amountIn := core.FromRawAmount(b.pool.Token0, event.Amount0)
+	if event.Amount1.Sign() > 0 {
+		amountIn = core.FromRawAmount(b.pool.Token1, event.Amount1)
+	}
+	result, err := b.pool.ApplySwap(amountIn, core.ExactInput, nil)
+	if err != nil {
+		return err
+	}
+	for _, segment := range result.Segments {
+		total := new(big.Int).Add(segment.BaseL, segment.ReinvestL)
+		if segment.FeeL.Sign() == 0 || total.Sign() == 0 {
+			continue
+		}
+		earning := new(big.Int).Set(b.feeL)
+		if b.position.TickLower <= segment.Tick && segment.Tick < b.position.TickUpper {
+			earning.Add(earning, b.position.Liquidity)
+		}
+		b.feeL.Add(b.feeL, earning.Mul(earning, segment.FeeL).Div(earning, total))
+	}
+	b.pool = result.Pool
+	return nil
+}
+
+// mint puts as much of the tokens kept aside as the range takes in a position
+func (b *Backtester) mint(tickLower, tickUpper int) error {
+	position, err := entities.FromAmounts(b.pool, tickLower, tickUpper, b.idle0, b.idle1, true)
+	if err != nil {
+		return err
+	}
+	amount0, amount1, err := position.MintAmounts()
+	if err != nil {
+		return err
+	}
+	pool, err := b.pool.ApplyLiquidity(tickLower, tickUpper, position.Liquidity)
+	if err != nil {
+		return err
+	}
+	b.position, err = entities.NewPosition(pool, position.Liquidity, tickLower, tickUpper)
+	if err != nil {
+		return err
+	}
+	b.pool = pool
+	b.idle0.Sub(b.idle0, amount0)
+	b.idle1.Sub(b.idle1, amount1)
+	return nil
+}
+
+// burn removes the position and its fees from the pool, keeping the tokens aside
+func (b *Backtester) burn() error {
+	amount0, err := b.position.Amount0()
+	if err != nil {
+		return err
+	}
+	amount1, err := b.position.Amount1()
+	if err != nil {
+		return err
+	}
+	fee0, fee1 := b.feeAmounts()
+	pool, err := b.pool.ApplyLiquidity(b.position.TickLower, b.position.TickUpper, new(big.Int).Neg(b.position.Liquidity))
+	if err != nil {
+		return err
+	}
+	pool.ReinvestL = new(big.Int).Sub(pool.ReinvestL, b.feeL)
+	b.pool = pool
+	b.idle0.Add(b.idle0, amount0.Quotient()).Add(b.idle0, fee0)
+	b.idle1.Add(b.idle1, amount1.Quotient()).Add(b.idle1, fee1)
+	b.feeL = big.NewInt(0)
+	return nil
+}
+
+// feeAmounts returns the tokens the reinvestment liquidity of the position can be burned for at the pool price
+func (b *Backtester) feeAmounts() (*big.Int, *big.Int) {
+	fee0 := new(big.Int).Mul(b.feeL, constants.Q96)
+	fee0.Div(fee0, b.pool.SqrtP)
+	fee1 := new(big.Int).Mul(b.feeL, b.pool.SqrtP)
+	fee1.Div(fee1, constants.Q96)
+	return fee0, fee1
+}
+
+func (b *Backtester) snapshot(event *Event) (*Snapshot, error) {
+	amount0, err := b.position.Amount0()
+	if err != nil {
+		return nil, err
+	}
+	amount1, err := b.position.Amount1()
+	if err != nil {
+		return nil, err
+	}
+	fee0, fee1 := b.feeAmounts()
+	total0 := new(big.Int).Add(amount0.Quotient(), fee0)
+	total0.Add(total0, b.idle0)
+	total1 := new(big.Int).Add(amount1.Quotient(), fee1)
+	total1.Add(total1, b.idle1)
+
+	hold0, hold1 := new(big.Int), new(big.Int)
+	if b.config.Amount0 != nil {
+		hold0.Set(b.config.Amount0)
+	}
+	if b.config.Amount1 != nil {
+		hold1.Set(b.config.Amount1)
+	}
+	return &Snapshot{
+		Event:     event,
+		Pool:      b.pool,
+		Position:  b.position,
+		FeeL:      new(big.Int).Set(b.feeL),
+		Fee0:      fee0,
+		Fee1:      fee1,
+		Idle0:     new(big.Int).Set(b.idle0),
+		Idle1:     new(big.Int).Set(b.idle1),
+		Value:     b.valueInToken1(total0, total1),
+		HoldValue: b.valueInToken1(hold0, hold1),
+	}, nil
+}
+
+// valueInToken1 returns the value of the amounts in token1 at the pool price
+func (b *Backtester) valueInToken1(amount0, amount1 *big.Int) *core.CurrencyAmount {
+	value := new(big.Int).Mul(amount0, b.pool.SqrtP)
+	value.Mul(value, b.pool.SqrtP).Div(value, constants.Q192).Add(value, amount1)
+	return core.FromRawAmount(b.pool.Token1, value)
+}
@@ -0,0 +1,121 @@
+package backtest
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/store"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+var (
+	token0   = core.NewToken(1, common.HexToAddress("0x0000000000000000000000000000000000000001"), 18, "t0", "token0")
+	token1   = core.NewToken(1, common.HexToAddress("0x0000000000000000000000000000000000000002"), 18, "t1", "token1")
+	oneEther = big.NewInt(1e18)
+)
+
+// fullRangePool returns a pool at price 1 with one ether of liquidity over the full range
+func fullRangePool(t *testing.T) *entities.Pool {
+	tickSpacing := constants.TickSpacings[constants.Fee1]
+	ticks := []entities.Tick{
+		{Index: entities.NearestUsableTick(utils.MinTick, tickSpacing), LiquidityNet: oneEther, LiquidityGross: oneEther},
+		{Index: entities.NearestUsableTick(utils.MaxTick, tickSpacing), LiquidityNet: new(big.Int).Neg(oneEther), LiquidityGross: oneEther},
+	}
+	p, err := entities.NewTickListDataProvider(ticks, tickSpacing)
+	assert.NoError(t, err)
+	pool, err := entities.NewPool(token0, token1, constants.Fee1, utils.EncodeSqrtRatioX96(constants.One, constants.One), oneEther, big.NewInt(0), 0, p)
+	assert.NoError(t, err)
+	return pool
+}
+
+func TestEventReader(t *testing.T) {
+	events, err := ReadEventsFile("testdata/events.jsonl")
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(events))
+	assert.Equal(t, EventSwap, events[0].Type)
+	assert.Equal(t, "-9800000000000000", events[0].Amount1.String())
+	assert.Equal(t, EventMint, events[2].Type)
+	assert.Equal(t, -2000, events[2].TickLower)
+	assert.Equal(t, "500000000000000000", events[2].Liquidity.String())
+
+	_, err = NewEventReader(strings.NewReader(`{"type":"collect"}`)).Next()
+	assert.ErrorIs(t, err, ErrUnknownEventType)
+	_, err = NewEventReader(strings.NewReader(`{"type":"swap","amount0":1,"amount1":1}`)).Next()
+	assert.ErrorIs(t, err, store.ErrInvalidSwap)
+	_, err = NewEventReader(strings.NewReader(`{"type":"mint","tickLower":-8,"tickUpper":8}`)).Next()
+	assert.ErrorIs(t, err, store.ErrInvalidLiquidity)
+
+	reader := NewEventReader(strings.NewReader("{\"type\":\"swap\",\"blockNumber\":2,\"amount0\":1,\"amount1\":-1}\n{\"type\":\"swap\",\"blockNumber\":1,\"amount0\":1,\"amount1\":-1}"))
+	_, err = reader.Next()
+	assert.NoError(t, err)
+	_, err = reader.Next()
+	assert.ErrorIs(t, err, ErrEventOutOfOrder)
+	assert.Contains(t, err.Error(), "line 2")
+}
+
+func TestBacktest(t *testing.T) {
+	amount := new(big.Int).Div(oneEther, big.NewInt(10))
+	config := &Config{TickLower: -400, TickUpper: 400, Amount0: amount, Amount1: amount}
+	result, err := RunFile(fullRangePool(t), "testdata/events.jsonl", config)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, len(result.Snapshots))
+	assert.Equal(t, 0, result.Rebalances)
+
+	// the position at price 1 takes as much of both tokens
+	start := result.Snapshots[0]
+	assert.Nil(t, start.Event)
+	assert.Equal(t, start.Idle0, start.Idle1)
+	assert.Equal(t, new(big.Int).Add(oneEther, start.Position.Liquidity), start.Pool.BaseL)
+	assert.Equal(t, start.HoldValue.Quotient(), new(big.Int).Add(start.Value.Quotient(), big.NewInt(1)))
+
+	// swapping there and back earns fees, worth more than holding
+	back := result.Snapshots[2]
+	assert.Positive(t, result.Snapshots[1].FeeL.Sign())
+	assert.Equal(t, 1, back.FeeL.Cmp(result.Snapshots[1].FeeL))
+	assert.Equal(t, 1, back.Pool.ReinvestL.Cmp(back.FeeL))
+	assert.Equal(t, 1, back.Value.Quotient().Cmp(back.HoldValue.Quotient()))
+
+	// liquidity minted by others dilutes the fees, and is burned back
+	assert.Equal(t, new(big.Int).Add(back.Pool.BaseL, big.NewInt(5e17)), result.Snapshots[3].Pool.BaseL)
+	assert.Equal(t, back.Pool.BaseL, result.Snapshots[4].Pool.BaseL)
+
+	// the large swap pushes the price out of the range, the position only holds token0
+	end := result.Snapshots[5]
+	assert.Less(t, end.Pool.CurrentTick, -400)
+	assert.Equal(t, oneEther, end.Pool.BaseL)
+	amount1, err := end.Position.Amount1()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, amount1.Quotient().Sign())
+
+	// recentering moves the position back in range, with its fees
+	config.Strategy = Recenter(800)
+	result, err = RunFile(fullRangePool(t), "testdata/events.jsonl", config)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Rebalances)
+	end = result.Snapshots[5]
+	assert.True(t, end.Rebalanced)
+	assert.LessOrEqual(t, end.Position.TickLower, end.Pool.CurrentTick)
+	assert.Less(t, end.Pool.CurrentTick, end.Position.TickUpper)
+	assert.Equal(t, 0, end.FeeL.Sign())
+	assert.Equal(t, new(big.Int).Add(oneEther, end.Position.Liquidity), end.Pool.BaseL)
+
+	// the strategy is called with the state after each event
+	var ticks []int
+	config.Strategy = StrategyFunc(func(snapshot *Snapshot) (int, int, bool) {
+		ticks = append(ticks, snapshot.Pool.CurrentTick)
+		return 0, 0, false
+	})
+	_, err = RunFile(fullRangePool(t), "testdata/events.jsonl", config)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, len(ticks))
+
+	_, err = NewBacktester(fullRangePool(t), &Config{TickLower: -400, TickUpper: 400})
+	assert.ErrorIs(t, err, ErrNoCapital)
+}
@@ -0,0 +1,140 @@
+package backtest
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/store"
+)
+
+var (
+	ErrUnknownEventType = errors.New("unknown event type")
+	ErrEventOutOfOrder  = errors.New("events must be in chronological order")
+)
+
+type EventType string
+
+const (
+	EventSwap EventType = "swap"
+	EventMint EventType = "mint"
+	EventBurn EventType = "burn"
+)
+
+/**
+ * An event of a pool, one JSON object per line of an event file. Amounts and liquidities are JSON numbers, which are
+ * decoded without loss of precision.
+ *
+ *	{"type":"swap","blockNumber":100,"timestamp":1700000000,"amount0":1000000,"amount1":-998000}
+ *	{"type":"mint","blockNumber":101,"timestamp":1700000012,"tickLower":-600,"tickUpper":600,"liquidity":5000000}
+ */
+type Event struct {
+	Type        EventType `json:"type"`
+	BlockNumber uint64    `json:"blockNumber"`
+	Timestamp   uint64    `json:"timestamp"`
+	LogIndex    uint      `json:"logIndex,omitempty"`
+
+	// swaps, as the deltaQty0 and deltaQty1 of the Swap event: the amounts paid into the pool, negative for the
+	// amounts taken out of it
+	Amount0 *big.Int `json:"amount0,omitempty"`
+	Amount1 *big.Int `json:"amount1,omitempty"`
+
+	// mints and burns
+	TickLower int      `json:"tickLower,omitempty"`
+	TickUpper int      `json:"tickUpper,omitempty"`
+	Liquidity *big.Int `json:"liquidity,omitempty"` // The liquidity added or removed
+}
+
+// Change returns the change of the pool state logged by the event, as the store replays it
+func (e *Event) Change() (store.Change, error) {
+	switch e.Type {
+	case EventSwap:
+		return &store.Swap{Amount0: e.Amount0, Amount1: e.Amount1}, nil
+	case EventMint:
+		return &store.Mint{TickLower: e.TickLower, TickUpper: e.TickUpper, Liquidity: e.Liquidity}, nil
+	case EventBurn:
+		return &store.Burn{TickLower: e.TickLower, TickUpper: e.TickUpper, Liquidity: e.Liquidity}, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnknownEventType, e.Type)
+}
+
+func (e *Event) validate() error {
+	change, err := e.Change()
+	if err != nil {
+		return err
+	}
+	return change.Validate()
+}
+
+// before returns whether the event happened before the other
+func (e *Event) before(other *Event) bool {
+	if e.BlockNumber != other.BlockNumber {
+		return e.BlockNumber < other.BlockNumber
+	}
+	return e.LogIndex < other.LogIndex
+}
+
+// Reads the events of a JSONL stream one by one, checking that they are valid and in chronological order
+type EventReader struct {
+	scanner *bufio.Scanner
+	line    int
+	last    *Event
+}
+
+func NewEventReader(r io.Reader) *EventReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<20)
+	return &EventReader{scanner: scanner}
+}
+
+// Next returns the next event, io.EOF once all the events have been read. Blank lines are skipped.
+func (r *EventReader) Next() (*Event, error) {
+	for r.scanner.Scan() {
+		r.line++
+		if len(r.scanner.Bytes()) == 0 {
+			continue
+		}
+		var event Event
+		if err := json.Unmarshal(r.scanner.Bytes(), &event); err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		if err := event.validate(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", r.line, err)
+		}
+		if r.last != nil && event.before(r.last) {
+			return nil, fmt.Errorf("line %d: %w", r.line, ErrEventOutOfOrder)
+		}
+		r.last = &event
+		return &event, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// ReadEventsFile reads all the events of a JSONL file
+func ReadEventsFile(path string) ([]*Event, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []*Event
+	reader := NewEventReader(f)
+	for {
+		event, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return events, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+}
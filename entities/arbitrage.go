@@ -0,0 +1,206 @@
+package entities
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+)
+
+// The input amount the sizing of an arbitrage searches up to when no maximum is given
+var defaultMaxArbitrageAmount = new(big.Int).Lsh(big.NewInt(1), 128)
+
+type ArbitrageOptions struct {
+	MaxHops       int       // the maximum number of pools in a cycle, 3 by default
+	MaxNumResults int       // how many results to return, 3 by default
+	MaxAmountIn   *big.Int  // optional, the largest input amount to size arbitrages up to
+	GasModel      *GasModel // optional, ranks arbitrages by their profit net of gas cost and drops the unprofitable ones when set
+}
+
+// An arbitrage through a cycle of pools, starting and ending with the same token
+type ArbitrageOf[P SwapPool[P]] struct {
+	Trade     *TradeOf[P]              // The exact input trade through the cycle, sized to maximize the profit
+	Profit    *entities.CurrencyAmount // The output amount of the trade less its input amount
+	NetProfit *entities.CurrencyAmount // The profit less the gas cost of the trade, the profit when no gas model is given
+}
+
+// Arbitrage is an arbitrage through Elastic pools
+type Arbitrage = ArbitrageOf[*Pool]
+
+/**
+ * Finds the profitable cycles through the pools that start and end with the given token, and sizes each of them to
+ * maximize its profit. The profit of a cycle is concave in its input amount, the optimal input is found by doubling
+ * the input until the profit decreases, then by a ternary search.
+ * @param pools the pools to consider, a pool is used at most once in a cycle
+ * @param token the token the cycles start and end with, and the profits are made in
+ * @param opts options for the search
+ * @returns The arbitrages, the most profitable first
+ */
+func FindArbitrages[P SwapPool[P]](pools []P, token *entities.Token, opts *ArbitrageOptions) ([]*ArbitrageOf[P], error) {
+	if len(pools) == 0 {
+		return nil, ErrNoPools
+	}
+	if opts == nil {
+		opts = &ArbitrageOptions{}
+	}
+	maxHops := opts.MaxHops
+	if maxHops == 0 {
+		maxHops = 3
+	}
+	if maxHops < 2 {
+		return nil, ErrInvalidMaxHops
+	}
+	maxNumResults := opts.MaxNumResults
+	if maxNumResults == 0 {
+		maxNumResults = 3
+	}
+	maxAmountIn := opts.MaxAmountIn
+	if maxAmountIn == nil {
+		maxAmountIn = defaultMaxArbitrageAmount
+	}
+	if opts.GasModel != nil {
+		if err := opts.GasModel.validate(token, token, entities.ExactInput); err != nil {
+			return nil, err
+		}
+	}
+
+	var arbitrages []*ArbitrageOf[P]
+	for _, cycle := range arbitrageCycles(pools, token, maxHops) {
+		route, err := NewRoute(cycle, token, token)
+		if err != nil {
+			return nil, err
+		}
+		// fees only lower the output, a cycle can not be profitable if the product of its mid prices is at most 1
+		midPrice, err := route.MidPrice()
+		if err != nil {
+			return nil, err
+		}
+		if midPrice.Numerator.Cmp(midPrice.Denominator) <= 0 {
+			continue
+		}
+
+		amountIn := optimalArbitrageInput(cycle, token, maxAmountIn)
+		if amountIn == nil {
+			continue
+		}
+		trade, err := FromRoute(route, entities.FromRawAmount(token, amountIn), entities.ExactInput)
+		if err != nil {
+			return nil, err
+		}
+		profit := trade.OutputAmount().Subtract(trade.InputAmount())
+		netProfit := profit
+		if opts.GasModel != nil {
+			netProfit = profit.Subtract(gasCost(opts.GasModel, trade))
+		}
+		if netProfit.Quotient().Sign() <= 0 {
+			continue
+		}
+		arbitrages = append(arbitrages, &ArbitrageOf[P]{Trade: trade, Profit: profit, NetProfit: netProfit})
+	}
+
+	sort.SliceStable(arbitrages, func(i, j int) bool {
+		return arbitrages[j].NetProfit.LessThan(arbitrages[i].NetProfit.Fraction)
+	})
+	if len(arbitrages) > maxNumResults {
+		arbitrages = arbitrages[:maxNumResults]
+	}
+	return arbitrages, nil
+}
+
+// arbitrageCycles returns the sequences of at least two and at most maxHops distinct pools going from token back to it
+func arbitrageCycles[P SwapPool[P]](pools []P, token *entities.Token, maxHops int) [][]P {
+	var (
+		cycles [][]P
+		path   []P
+		used   = make([]bool, len(pools))
+		visit  func(current *entities.Token)
+	)
+	visit = func(current *entities.Token) {
+		for i, pool := range pools {
+			if used[i] || !pool.InvolvesToken(current) {
+				continue
+			}
+			token0, token1 := pool.Tokens()
+			next := token0
+			if current.Equal(token0) {
+				next = token1
+			}
+			path = append(path, pool)
+			if next.Equal(token) {
+				if len(path) >= 2 {
+					cycles = append(cycles, append([]P(nil), path...))
+				}
+			} else if len(path) < maxHops {
+				used[i] = true
+				visit(next)
+				used[i] = false
+			}
+			path = path[:len(path)-1]
+		}
+	}
+	visit(token)
+	return cycles
+}
+
+// arbitrageProfit returns the profit of swapping the input amount through the cycle, nil if a swap fails
+func arbitrageProfit[P SwapPool[P]](cycle []P, token *entities.Token, amountIn *big.Int) *big.Int {
+	amount := entities.FromRawAmount(token, amountIn)
+	for _, pool := range cycle {
+		var err error
+		amount, _, err = pool.GetOutputAmount(amount, nil)
+		if err != nil {
+			return nil
+		}
+	}
+	return new(big.Int).Sub(amount.Quotient(), amountIn)
+}
+
+// optimalArbitrageInput returns the input amount maximizing the profit of the cycle, nil if no input is profitable
+func optimalArbitrageInput[P SwapPool[P]](cycle []P, token *entities.Token, maxAmountIn *big.Int) *big.Int {
+	profitOf := func(amountIn *big.Int) *big.Int {
+		return arbitrageProfit(cycle, token, amountIn)
+	}
+	// a failed swap ranks below any profit
+	less := func(a, b *big.Int) bool {
+		return a == nil && b != nil || a != nil && b != nil && a.Cmp(b) < 0
+	}
+
+	// double the input while the profit grows, small inputs can lose to rounding before the profit turns positive
+	lo, hi := big.NewInt(1), big.NewInt(1)
+	profit := profitOf(hi)
+	for hi.Cmp(maxAmountIn) < 0 {
+		next := new(big.Int).Lsh(hi, 1)
+		if next.Cmp(maxAmountIn) > 0 {
+			next.Set(maxAmountIn)
+		}
+		nextProfit := profitOf(next)
+		if nextProfit == nil || (profit != nil && profit.Sign() > 0 && less(nextProfit, profit)) {
+			hi = next
+			break
+		}
+		lo, hi, profit = hi, next, nextProfit
+	}
+
+	// the maximum is between lo and hi, narrow it down with a ternary search
+	three := big.NewInt(3)
+	for new(big.Int).Sub(hi, lo).Cmp(three) > 0 {
+		third := new(big.Int).Div(new(big.Int).Sub(hi, lo), three)
+		m1 := new(big.Int).Add(lo, third)
+		m2 := new(big.Int).Sub(hi, third)
+		if less(profitOf(m1), profitOf(m2)) {
+			lo = m1
+		} else {
+			hi = m2
+		}
+	}
+	var best, bestProfit *big.Int
+	for amountIn := new(big.Int).Set(lo); amountIn.Cmp(hi) <= 0; amountIn = new(big.Int).Add(amountIn, big.NewInt(1)) {
+		if p := profitOf(amountIn); less(bestProfit, p) {
+			best, bestProfit = amountIn, p
+		}
+	}
+	if bestProfit == nil || bestProfit.Sign() <= 0 {
+		return nil
+	}
+	return best
+}
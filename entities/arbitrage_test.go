@@ -0,0 +1,75 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+)
+
+func TestFindArbitrages(t *testing.T) {
+	cheap_0_1 := v2StylePool(token0, token1, entities.FromRawAmount(token0, big.NewInt(1_000_000)), entities.FromRawAmount(token1, big.NewInt(1_000_000)), constants.Fee004)
+	dear_0_1 := v2StylePool(token0, token1, entities.FromRawAmount(token0, big.NewInt(1_000_000)), entities.FromRawAmount(token1, big.NewInt(1_100_000)), constants.Fee03)
+
+	_, err := FindArbitrages[*Pool](nil, token0, nil)
+	assert.ErrorIs(t, err, ErrNoPools)
+
+	// no cycle is profitable when the pools agree on the price
+	arbitrages, err := FindArbitrages([]*Pool{cheap_0_1, pool_0_1}, token0, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, arbitrages)
+
+	// token0 is sold where token1 is cheap and bought back where it is dear
+	arbitrages, err = FindArbitrages([]*Pool{cheap_0_1, dear_0_1}, token0, nil)
+	assert.NoError(t, err)
+	if !assert.Equal(t, 1, len(arbitrages)) {
+		t.FailNow()
+	}
+	arbitrage := arbitrages[0]
+	route, err := arbitrage.Trade.Route()
+	assert.NoError(t, err)
+	assert.Equal(t, []*Pool{dear_0_1, cheap_0_1}, route.Pools)
+	assert.Equal(t, token0, arbitrage.Trade.OutputAmount().Currency)
+	assert.Equal(t, 1, arbitrage.Profit.Quotient().Sign())
+	assert.Equal(t, arbitrage.Profit, arbitrage.NetProfit)
+
+	// the input maximizes the profit
+	amountIn := arbitrage.Trade.InputAmount().Quotient()
+	for _, delta := range []int64{-1000, -1, 1, 1000} {
+		profit := arbitrageProfit(route.Pools, token0, new(big.Int).Add(amountIn, big.NewInt(delta)))
+		assert.True(t, profit.Cmp(arbitrage.Profit.Quotient()) <= 0, "the profit of %d more is %s", delta, profit)
+	}
+
+	// the input is bounded by the maximum
+	arbitrages, err = FindArbitrages([]*Pool{cheap_0_1, dear_0_1}, token0, &ArbitrageOptions{MaxAmountIn: big.NewInt(100)})
+	assert.NoError(t, err)
+	assert.True(t, arbitrages[0].Trade.InputAmount().Quotient().Cmp(big.NewInt(100)) <= 0)
+
+	// cycles through other tokens
+	dear_1_2 := v2StylePool(token1, token2, entities.FromRawAmount(token1, big.NewInt(1_000_000)), entities.FromRawAmount(token2, big.NewInt(1_100_000)), constants.Fee004)
+	cheap_0_2 := v2StylePool(token0, token2, entities.FromRawAmount(token0, big.NewInt(1_000_000)), entities.FromRawAmount(token2, big.NewInt(1_000_000)), constants.Fee004)
+	arbitrages, err = FindArbitrages([]*Pool{cheap_0_1, dear_1_2, cheap_0_2}, token0, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(arbitrages))
+	assert.Equal(t, []*entities.Token{token0, token1, token2, token0}, arbitrages[0].Trade.Swaps[0].Route.TokenPath)
+	_, err = FindArbitrages([]*Pool{cheap_0_1}, token0, &ArbitrageOptions{MaxHops: 1})
+	assert.ErrorIs(t, err, ErrInvalidMaxHops)
+
+	// the gas cost drops arbitrages it exceeds the profit of
+	gasModel := &GasModel{
+		BaseGas:       big.NewInt(100_000),
+		GasPrice:      big.NewInt(1),
+		GasTokenPrice: entities.NewPrice(Ether, token0, big.NewInt(1), big.NewInt(1)),
+	}
+	arbitrages, err = FindArbitrages([]*Pool{cheap_0_1, dear_0_1}, token0, &ArbitrageOptions{GasModel: gasModel})
+	assert.NoError(t, err)
+	assert.Empty(t, arbitrages)
+	gasModel.BaseGas = big.NewInt(1000)
+	arbitrages, err = FindArbitrages([]*Pool{cheap_0_1, dear_0_1}, token0, &ArbitrageOptions{GasModel: gasModel})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(arbitrages))
+	assert.Equal(t, arbitrages[0].Profit.Subtract(entities.FromRawAmount(token0, big.NewInt(1000))), arbitrages[0].NetProfit)
+}
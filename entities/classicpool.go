@@ -0,0 +1,231 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The amplification factor of a KyberSwap Classic pool without amplification, in basis points
+const ClassicBps = 10000
+
+// The precision of the fees of KyberSwap Classic pools, 1e18 being a fee of 100%
+var ClassicPrecision = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+var (
+	ErrInvalidAmpBps                 = errors.New("amplification must be at least 10000 bps")
+	ErrInvalidClassicFee             = errors.New("fee must be lower than the precision")
+	ErrInvalidVirtualReserves        = errors.New("virtual reserves must be at least the reserves")
+	ErrClassicInsufficientInput      = errors.New("DMMLibrary: INSUFFICIENT_INPUT_AMOUNT")
+	ErrClassicInsufficientOutput     = errors.New("DMMLibrary: INSUFFICIENT_OUTPUT_AMOUNT")
+	ErrClassicInsufficientLiquidity  = errors.New("DMMLibrary: INSUFFICIENT_LIQUIDITY")
+	ErrClassicReservesTokensMismatch = errors.New("reserves must be of two different tokens")
+)
+
+/**
+ * A KyberSwap Classic (DMM) pool. Swaps are priced against virtual reserves, the reserves amplified by AmpBps, and
+ * must not take more than the real reserves. The fee of dynamic fee pools moves with the volume traded, it is taken as
+ * returned by the pool's getTradeInfo and kept constant by swap simulations.
+ */
+type ClassicPool struct {
+	PoolAddress    common.Address // Pools of the same tokens are distinguished by their address, several can have different amplifications
+	Token0         *entities.Token
+	Token1         *entities.Token
+	Reserve0       *big.Int
+	Reserve1       *big.Int
+	VReserve0      *big.Int // The virtual reserve of token0, equal to Reserve0 in pools without amplification
+	VReserve1      *big.Int // The virtual reserve of token1, equal to Reserve1 in pools without amplification
+	AmpBps         uint32   // The amplification factor, in basis points
+	FeeInPrecision *big.Int // The swap fee, with ClassicPrecision being 100%
+
+	token0Price *entities.Price
+	token1Price *entities.Price
+}
+
+/**
+ * Constructs a KyberSwap Classic pool
+ * @param address the address of the pool
+ * @param reserveA the reserve of one of the tokens in the pool
+ * @param reserveB the reserve of the other token
+ * @param vReserveA the virtual reserve of the token of reserveA, ignored without amplification
+ * @param vReserveB the virtual reserve of the token of reserveB, ignored without amplification
+ * @param ampBps the amplification factor in basis points, ClassicBps for none
+ * @param feeInPrecision the swap fee, as returned by getTradeInfo
+ */
+func NewClassicPool(address common.Address, reserveA, reserveB *entities.CurrencyAmount, vReserveA, vReserveB *big.Int, ampBps uint32, feeInPrecision *big.Int) (*ClassicPool, error) {
+	tokenA, tokenB := reserveA.Currency.Wrapped(), reserveB.Currency.Wrapped()
+	if tokenA.Equal(tokenB) {
+		return nil, ErrClassicReservesTokensMismatch
+	}
+	if ampBps < ClassicBps {
+		return nil, ErrInvalidAmpBps
+	}
+	if feeInPrecision.Sign() < 0 || feeInPrecision.Cmp(ClassicPrecision) >= 0 {
+		return nil, ErrInvalidClassicFee
+	}
+	reserve0, reserve1 := reserveA.Quotient(), reserveB.Quotient()
+	if ampBps == ClassicBps {
+		vReserveA, vReserveB = reserve0, reserve1
+	}
+	if vReserveA.Cmp(reserve0) < 0 || vReserveB.Cmp(reserve1) < 0 {
+		return nil, ErrInvalidVirtualReserves
+	}
+	isSorted, err := tokenA.SortsBefore(tokenB)
+	if err != nil {
+		return nil, err
+	}
+	if !isSorted {
+		tokenA, tokenB = tokenB, tokenA
+		reserve0, reserve1 = reserve1, reserve0
+		vReserveA, vReserveB = vReserveB, vReserveA
+	}
+	return &ClassicPool{
+		PoolAddress:    address,
+		Token0:         tokenA,
+		Token1:         tokenB,
+		Reserve0:       reserve0,
+		Reserve1:       reserve1,
+		VReserve0:      vReserveA,
+		VReserve1:      vReserveB,
+		AmpBps:         ampBps,
+		FeeInPrecision: feeInPrecision,
+	}, nil
+}
+
+// ChainID returns the chain ID of the tokens in the pool
+func (p *ClassicPool) ChainID() uint {
+	return p.Token0.ChainId()
+}
+
+// Tokens returns the tokens of the pool, sorted
+func (p *ClassicPool) Tokens() (*entities.Token, *entities.Token) {
+	return p.Token0, p.Token1
+}
+
+// InvolvesToken returns true if the token is either token0 or token1
+func (p *ClassicPool) InvolvesToken(token *entities.Token) bool {
+	return p.Token0.Equal(token) || p.Token1.Equal(token)
+}
+
+// Token0Price returns the mid price of token0 in terms of token1, the ratio of the virtual reserves
+func (p *ClassicPool) Token0Price() *entities.Price {
+	if p.token0Price == nil {
+		p.token0Price = entities.NewPrice(p.Token0, p.Token1, p.VReserve0, p.VReserve1)
+	}
+	return p.token0Price
+}
+
+// Token1Price returns the mid price of token1 in terms of token0, the ratio of the virtual reserves
+func (p *ClassicPool) Token1Price() *entities.Price {
+	if p.token1Price == nil {
+		p.token1Price = entities.NewPrice(p.Token1, p.Token0, p.VReserve1, p.VReserve0)
+	}
+	return p.token1Price
+}
+
+// Address returns the address of the pool
+func (p *ClassicPool) Address() (common.Address, error) {
+	return p.PoolAddress, nil
+}
+
+// PathHop returns the address of the pool, the classic router taking the pools of a path along its tokens
+func (p *ClassicPool) PathHop() []byte {
+	return p.PoolAddress.Bytes()
+}
+
+// reserves returns the reserves and virtual reserves of the input and output tokens
+func (p *ClassicPool) reserves(zeroForOne bool) (reserveIn, reserveOut, vReserveIn, vReserveOut *big.Int) {
+	if zeroForOne {
+		return p.Reserve0, p.Reserve1, p.VReserve0, p.VReserve1
+	}
+	return p.Reserve1, p.Reserve0, p.VReserve1, p.VReserve0
+}
+
+/**
+ * Given an input amount of a token, returns the output amount as computed by DMMLibrary.getAmountOut, and the pool
+ * with its reserves updated after the swap
+ * @param inputAmount the input amount
+ * @param limitSqrtP ignored, classic pools have no price limit
+ */
+func (p *ClassicPool) GetOutputAmount(inputAmount *entities.CurrencyAmount, limitSqrtP *big.Int) (*entities.CurrencyAmount, *ClassicPool, error) {
+	if !(inputAmount.Currency.IsToken() && p.InvolvesToken(inputAmount.Currency.Wrapped())) {
+		return nil, nil, ErrTokenNotInvolved
+	}
+	amountIn := inputAmount.Quotient()
+	if amountIn.Sign() <= 0 {
+		return nil, nil, ErrClassicInsufficientInput
+	}
+	zeroForOne := inputAmount.Currency.Equal(p.Token0)
+	reserveIn, reserveOut, vReserveIn, vReserveOut := p.reserves(zeroForOne)
+	if reserveIn.Sign() <= 0 || reserveOut.Sign() <= 0 {
+		return nil, nil, ErrClassicInsufficientLiquidity
+	}
+
+	amountInWithFee := new(big.Int).Sub(ClassicPrecision, p.FeeInPrecision)
+	amountInWithFee.Mul(amountInWithFee, amountIn).Div(amountInWithFee, ClassicPrecision)
+	amountOut := new(big.Int).Mul(amountInWithFee, vReserveOut)
+	amountOut.Div(amountOut, new(big.Int).Add(vReserveIn, amountInWithFee))
+	if reserveOut.Cmp(amountOut) <= 0 {
+		return nil, nil, ErrClassicInsufficientLiquidity
+	}
+
+	outputToken := p.Token1
+	if !zeroForOne {
+		outputToken = p.Token0
+	}
+	return entities.FromRawAmount(outputToken, amountOut), p.swapped(zeroForOne, amountIn, amountOut), nil
+}
+
+/**
+ * Given a desired output amount of a token, returns the input amount as computed by DMMLibrary.getAmountIn, and the
+ * pool with its reserves updated after the swap
+ * @param outputAmount the output amount
+ * @param limitSqrtP ignored, classic pools have no price limit
+ */
+func (p *ClassicPool) GetInputAmount(outputAmount *entities.CurrencyAmount, limitSqrtP *big.Int) (*entities.CurrencyAmount, *ClassicPool, error) {
+	if !(outputAmount.Currency.IsToken() && p.InvolvesToken(outputAmount.Currency.Wrapped())) {
+		return nil, nil, ErrTokenNotInvolved
+	}
+	amountOut := outputAmount.Quotient()
+	if amountOut.Sign() <= 0 {
+		return nil, nil, ErrClassicInsufficientOutput
+	}
+	zeroForOne := outputAmount.Currency.Equal(p.Token1)
+	reserveIn, reserveOut, vReserveIn, vReserveOut := p.reserves(zeroForOne)
+	if reserveIn.Sign() <= 0 || reserveOut.Cmp(amountOut) <= 0 {
+		return nil, nil, ErrClassicInsufficientLiquidity
+	}
+
+	amountIn := new(big.Int).Mul(vReserveIn, amountOut)
+	amountIn.Div(amountIn, new(big.Int).Sub(vReserveOut, amountOut)).Add(amountIn, big.NewInt(1))
+	// the amount in before fees, rounded up
+	denominator := new(big.Int).Sub(ClassicPrecision, p.FeeInPrecision)
+	amountIn.Mul(amountIn, ClassicPrecision).Add(amountIn, denominator).Sub(amountIn, big.NewInt(1)).Div(amountIn, denominator)
+
+	inputToken := p.Token0
+	if !zeroForOne {
+		inputToken = p.Token1
+	}
+	return entities.FromRawAmount(inputToken, amountIn), p.swapped(zeroForOne, amountIn, amountOut), nil
+}
+
+// swapped returns the pool after a swap, the whole input amount including the fee being added to the reserves
+func (p *ClassicPool) swapped(zeroForOne bool, amountIn, amountOut *big.Int) *ClassicPool {
+	delta0, delta1 := amountIn, new(big.Int).Neg(amountOut)
+	if !zeroForOne {
+		delta0, delta1 = delta1, delta0
+	}
+	return &ClassicPool{
+		PoolAddress:    p.PoolAddress,
+		Token0:         p.Token0,
+		Token1:         p.Token1,
+		Reserve0:       new(big.Int).Add(p.Reserve0, delta0),
+		Reserve1:       new(big.Int).Add(p.Reserve1, delta1),
+		VReserve0:      new(big.Int).Add(p.VReserve0, delta0),
+		VReserve1:      new(big.Int).Add(p.VReserve1, delta1),
+		AmpBps:         p.AmpBps,
+		FeeInPrecision: p.FeeInPrecision,
+	}
+}
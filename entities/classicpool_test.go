@@ -0,0 +1,116 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ SwapPool[*ClassicPool] = (*ClassicPool)(nil)
+
+func newClassicPool(t *testing.T, address string, reserveA, reserveB *entities.CurrencyAmount, vReserveA, vReserveB int64, ampBps uint32, fee int64) *ClassicPool {
+	pool, err := NewClassicPool(common.HexToAddress(address), reserveA, reserveB, big.NewInt(vReserveA), big.NewInt(vReserveB), ampBps, big.NewInt(fee))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pool
+}
+
+func TestNewClassicPool(t *testing.T) {
+	pool := newClassicPool(t, "0xc1", entities.FromRawAmount(token1, big.NewInt(200)), entities.FromRawAmount(token0, big.NewInt(100)), 2000, 1000, 100000, 0)
+	assert.Equal(t, token0, pool.Token0)
+	assert.Equal(t, big.NewInt(100), pool.Reserve0)
+	assert.Equal(t, big.NewInt(1000), pool.VReserve0)
+	assert.Equal(t, big.NewInt(2000), pool.VReserve1)
+	assert.Equal(t, "2", pool.Token0Price().ToSignificant(1))
+
+	// the virtual reserves of pools without amplification are their reserves
+	pool = newClassicPool(t, "0xc1", entities.FromRawAmount(token0, big.NewInt(100)), entities.FromRawAmount(token1, big.NewInt(200)), 0, 0, ClassicBps, 0)
+	assert.Equal(t, big.NewInt(100), pool.VReserve0)
+
+	_, err := NewClassicPool(common.Address{}, entities.FromRawAmount(token0, big.NewInt(100)), entities.FromRawAmount(token1, big.NewInt(200)), big.NewInt(0), big.NewInt(0), 9999, big.NewInt(0))
+	assert.ErrorIs(t, err, ErrInvalidAmpBps)
+	_, err = NewClassicPool(common.Address{}, entities.FromRawAmount(token0, big.NewInt(100)), entities.FromRawAmount(token1, big.NewInt(200)), big.NewInt(10), big.NewInt(2000), 20000, big.NewInt(0))
+	assert.ErrorIs(t, err, ErrInvalidVirtualReserves)
+	_, err = NewClassicPool(common.Address{}, entities.FromRawAmount(token0, big.NewInt(100)), entities.FromRawAmount(token1, big.NewInt(200)), big.NewInt(0), big.NewInt(0), ClassicBps, ClassicPrecision)
+	assert.ErrorIs(t, err, ErrInvalidClassicFee)
+}
+
+func TestClassicPoolAmounts(t *testing.T) {
+	// 0.3% fee without amplification
+	pool := newClassicPool(t, "0xc1", entities.FromRawAmount(token0, big.NewInt(1_000_000)), entities.FromRawAmount(token1, big.NewInt(1_000_000)), 0, 0, ClassicBps, 3e15)
+	out, next, err := pool.GetOutputAmount(entities.FromRawAmount(token0, big.NewInt(1000)), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(996), out.Quotient())
+	assert.Equal(t, token1, out.Currency)
+	assert.Equal(t, big.NewInt(1_001_000), next.Reserve0)
+	assert.Equal(t, big.NewInt(999_004), next.Reserve1)
+	assert.Equal(t, next.Reserve1, next.VReserve1)
+
+	in, _, err := pool.GetInputAmount(entities.FromRawAmount(token1, big.NewInt(996)), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1000), in.Quotient())
+
+	// amplified 10 times, priced against the virtual reserves but bounded by the reserves
+	pool = newClassicPool(t, "0xc2", entities.FromRawAmount(token0, big.NewInt(1000)), entities.FromRawAmount(token1, big.NewInt(1000)), 10000, 10000, 100000, 0)
+	out, next, err = pool.GetOutputAmount(entities.FromRawAmount(token1, big.NewInt(100)), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(99), out.Quotient())
+	assert.Equal(t, big.NewInt(901), next.Reserve0)
+	assert.Equal(t, big.NewInt(9901), next.VReserve0)
+	assert.Equal(t, big.NewInt(10100), next.VReserve1)
+
+	_, _, err = pool.GetOutputAmount(entities.FromRawAmount(token0, big.NewInt(2000)), nil)
+	assert.ErrorIs(t, err, ErrClassicInsufficientLiquidity)
+	_, _, err = pool.GetInputAmount(entities.FromRawAmount(token0, big.NewInt(1000)), nil)
+	assert.ErrorIs(t, err, ErrClassicInsufficientLiquidity)
+	_, _, err = pool.GetOutputAmount(entities.FromRawAmount(token0, big.NewInt(0)), nil)
+	assert.ErrorIs(t, err, ErrClassicInsufficientInput)
+	_, _, err = pool.GetOutputAmount(entities.FromRawAmount(token2, big.NewInt(1)), nil)
+	assert.ErrorIs(t, err, ErrTokenNotInvolved)
+}
+
+func TestClassicPoolTrades(t *testing.T) {
+	classic_1_2 := newClassicPool(t, "0xc1", entities.FromRawAmount(token1, big.NewInt(100000)), entities.FromRawAmount(token2, big.NewInt(100000)), 0, 0, ClassicBps, 0)
+	shallow_1_2 := newClassicPool(t, "0xc2", entities.FromRawAmount(token1, big.NewInt(10)), entities.FromRawAmount(token2, big.NewInt(10)), 0, 0, ClassicBps, 0)
+
+	// classic pools route along elastic pools
+	pools := []AnyPool{AsAnyPool(pool_0_1), AsAnyPool(classic_1_2), AsAnyPool(shallow_1_2)}
+	trades, err := BestTradeExactInOf(pools, entities.FromRawAmount(token0, big.NewInt(1000)), token2, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(trades))
+	assert.Equal(t, []*entities.Token{token0, token1, token2}, trades[0].Swaps[0].Route.TokenPath)
+	classic, ok := PoolAs[*ClassicPool](trades[0].Swaps[0].Route.Pools[1])
+	assert.True(t, ok)
+	assert.Equal(t, classic_1_2, classic)
+
+	// pools without enough reserves for the output are skipped
+	trades, err = BestTradeExactOutOf(pools, token0, entities.FromRawAmount(token2, big.NewInt(100)), nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(trades))
+
+	// as are empty pools, whichever amount is fixed
+	empty_1_2 := newClassicPool(t, "0xc3", entities.FromRawAmount(token1, big.NewInt(0)), entities.FromRawAmount(token2, big.NewInt(0)), 0, 0, ClassicBps, 0)
+	_, _, err = empty_1_2.GetOutputAmount(entities.FromRawAmount(token1, big.NewInt(1000)), nil)
+	assert.ErrorIs(t, err, ErrClassicInsufficientLiquidity)
+	pools = []AnyPool{AsAnyPool(pool_0_1), AsAnyPool(empty_1_2), AsAnyPool(classic_1_2)}
+	trades, err = BestTradeExactInOf(pools, entities.FromRawAmount(token0, big.NewInt(1000)), token2, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(trades))
+	classic, ok = PoolAs[*ClassicPool](trades[0].Swaps[0].Route.Pools[1])
+	assert.True(t, ok)
+	assert.Equal(t, classic_1_2, classic)
+	trades, err = BestTradeExactOutOf(pools, token0, entities.FromRawAmount(token2, big.NewInt(100)), nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(trades))
+
+	route, err := NewRoute([]*ClassicPool{classic_1_2}, token1, token2)
+	assert.NoError(t, err)
+	trade, err := ExactIn(route, entities.FromRawAmount(token1, big.NewInt(1000)))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(990), trade.OutputAmount().Quotient())
+	assert.Equal(t, 0, trade.Swaps[0].TicksCrossed)
+}
@@ -0,0 +1,159 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+var (
+	ErrInvalidPhaseTime     = errors.New("phase end time must be after its start time")
+	ErrPhaseRewardsMismatch = errors.New("reward tokens, amounts and sums per liquidity have different lengths")
+	ErrStakeRewardsMismatch = errors.New("stake rewards do not match the phase reward tokens")
+)
+
+/**
+ * A phase of an Elastic farm. The reward amounts are distributed linearly between the start and end time to the
+ * farming liquidity staked at each second, as accumulated in SumRewardPerLiquidity.
+ */
+type FarmPhase struct {
+	StartTime             uint64
+	EndTime               uint64
+	RewardTokens          []*entities.Token
+	RewardAmounts         []*big.Int // The total amount of each reward token distributed over the phase
+	TotalLiquidity        *big.Int   // The farming liquidity staked in the farm
+	SumRewardPerLiquidity []*big.Int // The rewards per liquidity accumulated since the start, as Q96 values
+	LastTouchedTime       uint64     // The time SumRewardPerLiquidity was last updated at
+}
+
+/**
+ * Constructs a phase that has not accumulated any rewards yet
+ * @param startTime the time the phase starts distributing rewards
+ * @param endTime the time the phase stops distributing rewards
+ * @param rewardTokens the tokens rewarded
+ * @param rewardAmounts the total amount of each token distributed over the phase
+ */
+func NewFarmPhase(startTime, endTime uint64, rewardTokens []*entities.Token, rewardAmounts []*big.Int) (*FarmPhase, error) {
+	if endTime <= startTime {
+		return nil, ErrInvalidPhaseTime
+	}
+	if len(rewardTokens) != len(rewardAmounts) {
+		return nil, ErrPhaseRewardsMismatch
+	}
+	sums := make([]*big.Int, len(rewardTokens))
+	for i := range sums {
+		sums[i] = big.NewInt(0)
+	}
+	return &FarmPhase{
+		StartTime:             startTime,
+		EndTime:               endTime,
+		RewardTokens:          rewardTokens,
+		RewardAmounts:         rewardAmounts,
+		TotalLiquidity:        big.NewInt(0),
+		SumRewardPerLiquidity: sums,
+		LastTouchedTime:       startTime,
+	}, nil
+}
+
+// RewardRates returns the amount of each reward token distributed per second
+func (p *FarmPhase) RewardRates() []*entities.Fraction {
+	duration := new(big.Int).SetUint64(p.EndTime - p.StartTime)
+	rates := make([]*entities.Fraction, len(p.RewardAmounts))
+	for i, amount := range p.RewardAmounts {
+		rates[i] = entities.NewFraction(amount, duration)
+	}
+	return rates
+}
+
+/**
+ * Returns the rewards per liquidity accumulated by the given time, as the farm contract updates them when touched.
+ * Nothing accumulates while no liquidity is staked or outside of the phase.
+ * Mirrors KyberSwapFarmingV2._updateFarmSumRewardPerLiquidity, which adds
+ * LMMath.calcSumRewardPerLiquidity = rewardAmount * joinedDuration * Q96 / (totalDuration * totalLiquidity) per reward.
+ * @param timestamp the block time
+ */
+func (p *FarmPhase) SumRewardPerLiquidityAt(timestamp uint64) ([]*big.Int, error) {
+	if len(p.RewardAmounts) != len(p.SumRewardPerLiquidity) {
+		return nil, ErrPhaseRewardsMismatch
+	}
+	if timestamp > p.EndTime {
+		timestamp = p.EndTime
+	}
+	lastTouched := p.LastTouchedTime
+	if lastTouched < p.StartTime {
+		lastTouched = p.StartTime
+	}
+	sums := make([]*big.Int, len(p.SumRewardPerLiquidity))
+	for i, sum := range p.SumRewardPerLiquidity {
+		sums[i] = new(big.Int).Set(sum)
+	}
+	if timestamp <= lastTouched || p.TotalLiquidity.Sign() == 0 {
+		return sums, nil
+	}
+	elapsed := new(big.Int).SetUint64(timestamp - lastTouched)
+	denominator := new(big.Int).Mul(new(big.Int).SetUint64(p.EndTime-p.StartTime), p.TotalLiquidity)
+	for i, amount := range p.RewardAmounts {
+		sums[i].Add(sums[i], utils.MulDiv(new(big.Int).Mul(amount, elapsed), constants.Q96, denominator))
+	}
+	return sums, nil
+}
+
+// A position staked in a farm phase
+type StakedPosition struct {
+	Position                  *Position
+	Liquidity                 *big.Int   // The farming liquidity of the stake, the position's liquidity scaled by the weight of its range
+	LastSumRewardPerLiquidity []*big.Int // The phase's sums per liquidity when the stake was last updated
+	RewardUnclaimed           []*big.Int // The rewards accrued but not claimed when the stake was last updated
+}
+
+/**
+ * Constructs the stake of a position joining a phase
+ * @param position the staked position
+ * @param weight the weight of the farm range the position is staked in, 1 if the farm has no weights
+ * @param phase the phase joined
+ * @param timestamp the time the position is staked at
+ */
+func NewStakedPosition(position *Position, weight *big.Int, phase *FarmPhase, timestamp uint64) (*StakedPosition, error) {
+	sums, err := phase.SumRewardPerLiquidityAt(timestamp)
+	if err != nil {
+		return nil, err
+	}
+	unclaimed := make([]*big.Int, len(sums))
+	for i := range unclaimed {
+		unclaimed[i] = big.NewInt(0)
+	}
+	return &StakedPosition{
+		Position:                  position,
+		Liquidity:                 new(big.Int).Mul(position.Liquidity, weight),
+		LastSumRewardPerLiquidity: sums,
+		RewardUnclaimed:           unclaimed,
+	}, nil
+}
+
+/**
+ * Returns the rewards the stake can claim at the given time, rounded down like the farm contract does.
+ * Mirrors KyberSwapFarmingV2._updateRewardData, which accrues
+ * LMMath.calcRewardAmount = mulDivFloor(curSumRewardPerLiquidity - lastSumRewardPerLiquidity, liquidity, Q96).
+ * @param phase the phase the position is staked in
+ * @param timestamp the block time
+ */
+func (s *StakedPosition) PendingRewards(phase *FarmPhase, timestamp uint64) ([]*entities.CurrencyAmount, error) {
+	sums, err := phase.SumRewardPerLiquidityAt(timestamp)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.LastSumRewardPerLiquidity) != len(sums) || len(s.RewardUnclaimed) != len(sums) {
+		return nil, ErrStakeRewardsMismatch
+	}
+	rewards := make([]*entities.CurrencyAmount, len(sums))
+	for i, sum := range sums {
+		delta := new(big.Int).Sub(sum, s.LastSumRewardPerLiquidity[i])
+		reward := utils.MulDiv(delta, s.Liquidity, constants.Q96)
+		rewards[i] = entities.FromRawAmount(phase.RewardTokens[i], reward.Add(reward, s.RewardUnclaimed[i]))
+	}
+	return rewards, nil
+}
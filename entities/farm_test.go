@@ -0,0 +1,95 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFarmPendingRewards(t *testing.T) {
+	_, err := NewFarmPhase(10, 10, nil, nil)
+	assert.ErrorIs(t, err, ErrInvalidPhaseTime)
+	_, err = NewFarmPhase(0, 10, []*entities.Token{USDC}, nil)
+	assert.ErrorIs(t, err, ErrPhaseRewardsMismatch)
+
+	phase, err := NewFarmPhase(1000, 2000, []*entities.Token{USDC, DAI}, []*big.Int{OneEther, big.NewInt(999)})
+	assert.NoError(t, err)
+	rates := phase.RewardRates()
+	assert.Equal(t, "1000000000000000", rates[0].Quotient().String())
+
+	pool := newTestPoolFee004()
+	position, err := NewPosition(pool, OneEther, -80, 80)
+	assert.NoError(t, err)
+
+	// a stake joining before the start accrues nothing until the phase starts
+	a, err := NewStakedPosition(position, big.NewInt(1), phase, 500)
+	assert.NoError(t, err)
+	phase.TotalLiquidity = new(big.Int).Set(a.Liquidity)
+	rewards, err := a.PendingRewards(phase, 900)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, rewards[0].Quotient().Sign())
+
+	// a second stake with twice the weight joins half way
+	sums, err := phase.SumRewardPerLiquidityAt(1500)
+	assert.NoError(t, err)
+	phase.SumRewardPerLiquidity, phase.LastTouchedTime = sums, 1500
+	b, err := NewStakedPosition(position, big.NewInt(2), phase, 1500)
+	assert.NoError(t, err)
+	phase.TotalLiquidity.Add(phase.TotalLiquidity, b.Liquidity)
+
+	rewards, err = a.PendingRewards(phase, 1500)
+	assert.NoError(t, err)
+	assert.Equal(t, "500000000000000000", rewards[0].Quotient().String())
+	assert.True(t, rewards[0].Currency.Equal(USDC))
+
+	// rewards stop accruing at the end of the phase
+	rewardsA, err := a.PendingRewards(phase, 5000)
+	assert.NoError(t, err)
+	rewardsB, err := b.PendingRewards(phase, 2000)
+	assert.NoError(t, err)
+	assert.Equal(t, "666666666666666666", rewardsA[0].Quotient().String())
+	assert.Equal(t, "333333333333333333", rewardsB[0].Quotient().String())
+	// the contract rounds down, so the stakes never get more than the phase distributes
+	total := new(big.Int).Add(rewardsA[1].Quotient(), rewardsB[1].Quotient())
+	assert.True(t, total.Cmp(big.NewInt(999)) <= 0)
+
+	// unclaimed rewards are added to the accrued ones
+	b.RewardUnclaimed[0] = big.NewInt(2)
+	rewardsB, err = b.PendingRewards(phase, 2000)
+	assert.NoError(t, err)
+	assert.Equal(t, "333333333333333335", rewardsB[0].Quotient().String())
+
+	_, err = (&StakedPosition{Liquidity: OneEther}).PendingRewards(phase, 2000)
+	assert.ErrorIs(t, err, ErrStakeRewardsMismatch)
+}
+
+// Values worked out with the integer arithmetic of LMMath.calcSumRewardPerLiquidity and LMMath.calcRewardAmount
+func TestFarmRewardsMatchContract(t *testing.T) {
+	amount, _ := new(big.Int).SetString("123456789012345678901", 10)
+	phase, err := NewFarmPhase(1680000000, 1680604800, []*entities.Token{USDC}, []*big.Int{amount})
+	assert.NoError(t, err)
+	phase.TotalLiquidity = big.NewInt(3141592653589793)
+	phase.SumRewardPerLiquidity = []*big.Int{big.NewInt(987654321987654321)}
+	phase.LastTouchedTime = 1680001000
+
+	sums, err := phase.SumRewardPerLiquidityAt(1680086400)
+	assert.NoError(t, err)
+	assert.Equal(t, "439633498255361533142616514956214", sums[0].String())
+	sums, err = phase.SumRewardPerLiquidityAt(1690000000)
+	assert.NoError(t, err)
+	assert.Equal(t, "3108322087196566530579758001528831", sums[0].String())
+
+	stake := &StakedPosition{
+		Liquidity:                 big.NewInt(3703703670369),
+		LastSumRewardPerLiquidity: []*big.Int{big.NewInt(987654321987654321)},
+		RewardUnclaimed:           []*big.Int{big.NewInt(0)},
+	}
+	rewards, err := stake.PendingRewards(phase, 1680086400)
+	assert.NoError(t, err)
+	assert.Equal(t, "20551684520165238", rewards[0].Quotient().String())
+	rewards, err = stake.PendingRewards(phase, 1690000000)
+	assert.NoError(t, err)
+	assert.Equal(t, "145305703902526592", rewards[0].Quotient().String())
+}
@@ -0,0 +1,139 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+)
+
+var (
+	ErrGasPriceCurrencyMismatch = errors.New("gas token price must be quoted in the trade's output currency for exact input or input currency for exact output")
+	ErrTradeTypeMismatch        = errors.New("trade type mismatch")
+	ErrGasTokenPriceRequired    = errors.New("gas model has no gas token price")
+)
+
+/**
+ * GasModel estimates the gas used by a trade and its cost expressed in one of the trade's currencies.
+ *
+ * Gas is estimated per swap as BaseGas + HopGas * number of pools + TickCrossedGas * number of initialized
+ * ticks crossed, as reported by the swap simulation.
+ */
+type GasModel struct {
+	BaseGas        *big.Int        // The gas used by every swap, regardless of its route
+	HopGas         *big.Int        // The gas used by each pool a swap goes through
+	TickCrossedGas *big.Int        // The gas used by each initialized tick crossed
+	GasPrice       *big.Int        // The gas price, in wei
+	GasTokenPrice  *entities.Price // The price of the gas token, quoted in the output currency for exact input trades and the input currency for exact output trades
+}
+
+// EstimateGas returns the gas units the given trade is expected to use
+func (g *GasModel) EstimateGas(trade *Trade) *big.Int {
+	return estimateGas(g, trade)
+}
+
+func estimateGas[P SwapPool[P]](g *GasModel, trade *TradeOf[P]) *big.Int {
+	gas := new(big.Int)
+	for _, swap := range trade.Swaps {
+		gas.Add(gas, orZero(g.BaseGas))
+		gas.Add(gas, new(big.Int).Mul(orZero(g.HopGas), big.NewInt(int64(len(swap.Route.Pools)))))
+		gas.Add(gas, new(big.Int).Mul(orZero(g.TickCrossedGas), big.NewInt(int64(swap.TicksCrossed))))
+	}
+	return gas
+}
+
+// GasCost returns the cost of the gas used by the given trade in the quote currency of GasTokenPrice
+func (g *GasModel) GasCost(trade *Trade) (*entities.CurrencyAmount, error) {
+	if err := g.validate(trade.InputAmount().Currency, trade.OutputAmount().Currency, trade.TradeType); err != nil {
+		return nil, err
+	}
+	return gasCost(g, trade), nil
+}
+
+// NetOutputAmount returns the output amount of an exact input trade minus its gas cost
+func (g *GasModel) NetOutputAmount(trade *Trade) (*entities.CurrencyAmount, error) {
+	if trade.TradeType != entities.ExactInput {
+		return nil, ErrTradeTypeMismatch
+	}
+	cost, err := g.GasCost(trade)
+	if err != nil {
+		return nil, err
+	}
+	return trade.OutputAmount().Subtract(cost), nil
+}
+
+// GrossInputAmount returns the input amount of an exact output trade plus its gas cost
+func (g *GasModel) GrossInputAmount(trade *Trade) (*entities.CurrencyAmount, error) {
+	if trade.TradeType != entities.ExactOutput {
+		return nil, ErrTradeTypeMismatch
+	}
+	cost, err := g.GasCost(trade)
+	if err != nil {
+		return nil, err
+	}
+	return trade.InputAmount().Add(cost), nil
+}
+
+// validate ensures gas costs can be expressed in the currency the trades are ranked by
+func (g *GasModel) validate(currencyIn, currencyOut entities.Currency, tradeType entities.TradeType) error {
+	rankedBy := currencyOut
+	if tradeType == entities.ExactOutput {
+		rankedBy = currencyIn
+	}
+	if g.GasTokenPrice == nil {
+		return ErrGasTokenPriceRequired
+	}
+	if !g.GasTokenPrice.QuoteCurrency.Wrapped().Equal(rankedBy.Wrapped()) {
+		return ErrGasPriceCurrencyMismatch
+	}
+	return nil
+}
+
+func gasCost[P SwapPool[P]](g *GasModel, trade *TradeOf[P]) *entities.CurrencyAmount {
+	gasWei := new(big.Int).Mul(estimateGas(g, trade), orZero(g.GasPrice))
+	cost := g.GasTokenPrice.Fraction.Multiply(entities.NewFraction(gasWei, big.NewInt(1)))
+	currency := trade.OutputAmount().Currency
+	if trade.TradeType == entities.ExactOutput {
+		currency = trade.InputAmount().Currency
+	}
+	return entities.FromFractionalAmount(currency, cost.Numerator, cost.Denominator)
+}
+
+/**
+ * Returns a comparator ranking trades by their output net of gas cost for exact input trades, or their input plus gas
+ * cost for exact output trades, falling back to tradeComparator when those are equal. The gas model must have been
+ * validated against the trades' currencies.
+ */
+func gasTradeComparator[P SwapPool[P]](g *GasModel) func(a, b *TradeOf[P]) int {
+	return func(a, b *TradeOf[P]) int {
+		if a.TradeType == entities.ExactInput {
+			netA := a.OutputAmount().Subtract(gasCost(g, a))
+			netB := b.OutputAmount().Subtract(gasCost(g, b))
+			if !netA.EqualTo(netB.Fraction) {
+				// trade A nets more output than trade B, so A should come first
+				if netB.LessThan(netA.Fraction) {
+					return -1
+				}
+				return 1
+			}
+		} else {
+			grossA := a.InputAmount().Add(gasCost(g, a))
+			grossB := b.InputAmount().Add(gasCost(g, b))
+			if !grossA.EqualTo(grossB.Fraction) {
+				// trade A costs less than trade B, so A should come first
+				if grossA.LessThan(grossB.Fraction) {
+					return -1
+				}
+				return 1
+			}
+		}
+		return tradeComparator(a, b)
+	}
+}
+
+func orZero(i *big.Int) *big.Int {
+	if i == nil {
+		return new(big.Int)
+	}
+	return i
+}
@@ -0,0 +1,145 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+func newGasModel(gasPrice int64, quote entities.Currency) *GasModel {
+	return &GasModel{
+		BaseGas:        big.NewInt(0),
+		HopGas:         big.NewInt(1),
+		TickCrossedGas: big.NewInt(2),
+		GasPrice:       big.NewInt(gasPrice),
+		GasTokenPrice:  entities.NewPrice(entities.WETH9[1], quote, big.NewInt(1), big.NewInt(1)),
+	}
+}
+
+func TestTicksCrossed(t *testing.T) {
+	liquidity := big.NewInt(1e18)
+	ticks := []Tick{
+		{Index: -887220, LiquidityNet: liquidity, LiquidityGross: liquidity},
+		{Index: -60, LiquidityNet: liquidity, LiquidityGross: liquidity},
+		{Index: 60, LiquidityNet: new(big.Int).Neg(liquidity), LiquidityGross: liquidity},
+		{Index: 887220, LiquidityNet: new(big.Int).Neg(liquidity), LiquidityGross: liquidity},
+	}
+	p, err := NewTickListDataProvider(ticks, constants.TickSpacings[constants.Fee03])
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := NewPool(token0, token1, constants.Fee03, utils.EncodeSqrtRatioX96(constants.One, constants.One), new(big.Int).Mul(liquidity, big.NewInt(2)), big.NewInt(0), 0, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := NewRoute([]*Pool{pool}, token1, token0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// stays within the current range
+	trade, err := FromRoute(r, entities.FromRawAmount(token1, big.NewInt(1e15)), entities.ExactInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0, trade.Swaps[0].TicksCrossed)
+
+	// crosses the upper tick of the current range
+	trade, err = FromRoute(r, entities.FromRawAmount(token1, big.NewInt(1e16)), entities.ExactInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, trade.Swaps[0].TicksCrossed)
+	assert.Equal(t, big.NewInt(3), newGasModel(1, token0).EstimateGas(trade))
+
+	trades, err := FromRoutes([]*WrappedRoute{{Route: r, Amount: entities.FromRawAmount(token0, big.NewInt(1e16))}}, entities.ExactOutput)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 1, trades.Swaps[0].TicksCrossed)
+}
+
+func TestGasCost(t *testing.T) {
+	r, err := NewRoute([]*Pool{pool_0_1, pool_1_3}, token0, token3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	trade, err := FromRoute(r, entities.FromRawAmount(token0, big.NewInt(100)), entities.ExactInput)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	model := newGasModel(10, token3)
+	assert.Equal(t, big.NewInt(2), model.EstimateGas(trade))
+	cost, err := model.GasCost(trade)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(20), cost.Quotient())
+	net, err := model.NetOutputAmount(trade)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(87), net.Quotient())
+
+	_, err = model.GrossInputAmount(trade)
+	assert.ErrorIs(t, err, ErrTradeTypeMismatch)
+	_, err = newGasModel(10, token0).GasCost(trade)
+	assert.ErrorIs(t, err, ErrGasPriceCurrencyMismatch)
+}
+
+func TestBestTradeWithGasModel(t *testing.T) {
+	pools := []*Pool{pool_0_1, pool_0_3, pool_1_3}
+
+	_, err := BestTradeExactIn(pools, entities.FromRawAmount(token0, big.NewInt(100)), token3, &BestTradeOptions{MaxNumResults: 3, MaxHops: 3, GasModel: newGasModel(1, token0)}, nil, nil, nil)
+	assert.ErrorIs(t, err, ErrGasPriceCurrencyMismatch)
+	_, err = BestTradeExactIn(pools, entities.FromRawAmount(token0, big.NewInt(100)), token3, &BestTradeOptions{MaxNumResults: 3, MaxHops: 3, GasModel: &GasModel{GasPrice: big.NewInt(1)}}, nil, nil, nil)
+	assert.ErrorIs(t, err, ErrGasTokenPriceRequired)
+	_, err = BestTradeExactOut(pools, token0, entities.FromRawAmount(token3, big.NewInt(100)), &BestTradeOptions{MaxNumResults: 3, MaxHops: 3, GasModel: &GasModel{GasPrice: big.NewInt(1)}}, nil, nil, nil)
+	assert.ErrorIs(t, err, ErrGasTokenPriceRequired)
+
+	// cheap gas keeps the 2 hop route first
+	result, err := BestTradeExactIn(pools, entities.FromRawAmount(token0, big.NewInt(100)), token3, &BestTradeOptions{MaxNumResults: 3, MaxHops: 3, GasModel: newGasModel(10, token3)}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, []*entities.Token{token0, token1, token3}, result[0].Swaps[0].Route.TokenPath)
+
+	// expensive gas prefers the direct route even though it outputs less
+	result, err = BestTradeExactIn(pools, entities.FromRawAmount(token0, big.NewInt(100)), token3, &BestTradeOptions{MaxNumResults: 3, MaxHops: 3, GasModel: newGasModel(30, token3)}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, []*entities.Token{token0, token3}, result[0].Swaps[0].Route.TokenPath)
+	assert.True(t, result[0].OutputAmount().LessThan(result[1].OutputAmount().Fraction))
+
+	f, err := NewRouteFinder(pools, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err = f.BestTradeExactIn(entities.FromRawAmount(token0, big.NewInt(100)), token3, &BestTradeOptions{MaxNumResults: 1, MaxHops: 3, GasModel: newGasModel(30, token3)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*entities.Token{token0, token3}, result[0].Swaps[0].Route.TokenPath)
+
+	// exact output trades are ranked by input plus gas, in the input currency
+	result, err = BestTradeExactOut(pools, token0, entities.FromRawAmount(token3, big.NewInt(100)), &BestTradeOptions{MaxNumResults: 3, MaxHops: 3, GasModel: newGasModel(30, token0)}, nil, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(result))
+	assert.Equal(t, []*entities.Token{token0, token3}, result[0].Swaps[0].Route.TokenPath)
+	gross, err := newGasModel(30, token0).GrossInputAmount(result[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big.NewInt(120), gross.Quotient())
+}
@@ -48,6 +48,7 @@ type Pool struct {
 	NearestCurrentTick int
 	Ticks              map[int]TickData
 	InitializedTicks   map[int]LinkedListData
+	Math               utils.MathMode // The arithmetic used to simulate swaps, big.Int by default, MathStrict to fail where the contract reverts
 
 	token0Price *entities.Price
 	token1Price *entities.Price
@@ -189,6 +190,21 @@ func (p *Pool) ChainID() uint {
 	return p.Token0.ChainId()
 }
 
+// Tokens returns the tokens of the pool, sorted
+func (p *Pool) Tokens() (*entities.Token, *entities.Token) {
+	return p.Token0, p.Token1
+}
+
+// Address returns the address of the pool deployed by the default factory
+func (p *Pool) Address() (common.Address, error) {
+	return GetAddress(p.Token0, p.Token1, p.Fee, "")
+}
+
+// PathHop returns the fee of the pool as the uint24 identifying it in a packed swap path
+func (p *Pool) PathHop() []byte {
+	return FeePathHop(p.Fee)
+}
+
 /**
  * Given an input amount of a token, return the computed output amount, and a pool with state updated after the trade
  * @param inputAmount The input amount for which to quote the output amount
@@ -198,17 +214,26 @@ func (p *Pool) ChainID() uint {
 func (p *Pool) GetOutputAmount(
 	inputAmount *entities.CurrencyAmount, limitSqrtP *big.Int,
 ) (*entities.CurrencyAmount, *Pool, error) {
+	outputAmount, newPoolState, _, err := p.getOutputAmount(inputAmount, limitSqrtP)
+	return outputAmount, newPoolState, err
+}
+
+// getOutputAmount is GetOutputAmount that also returns the number of initialized ticks crossed by the swap
+func (p *Pool) getOutputAmount(
+	inputAmount *entities.CurrencyAmount, limitSqrtP *big.Int,
+) (*entities.CurrencyAmount, *Pool, int, error) {
 	if !(inputAmount.Currency.IsToken() && p.InvolvesToken(inputAmount.Currency.Wrapped())) {
-		return nil, nil, ErrTokenNotInvolved
+		return nil, nil, 0, ErrTokenNotInvolved
 	}
 	zeroForOne := inputAmount.Currency.Equal(p.Token0)
-	returnedAmount, baseL, reinvestL, sqrtP, currentTick, nextTick, err := p.swap(
+	returnedAmount, baseL, reinvestL, sqrtP, currentTick, nextTick, ticksCrossed, err := p.swap(
 		zeroForOne,
 		inputAmount.Quotient(),
 		limitSqrtP,
+		nil,
 	)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
 	var outputToken *entities.Token
@@ -220,7 +245,7 @@ func (p *Pool) GetOutputAmount(
 
 	newPoolState := p._updatePoolData(baseL, reinvestL, sqrtP, currentTick, nextTick)
 
-	return entities.FromRawAmount(outputToken, new(big.Int).Mul(returnedAmount, constants.NegativeOne)), newPoolState, nil
+	return entities.FromRawAmount(outputToken, new(big.Int).Mul(returnedAmount, constants.NegativeOne)), newPoolState, ticksCrossed, nil
 }
 
 /**
@@ -232,17 +257,26 @@ func (p *Pool) GetOutputAmount(
 func (p *Pool) GetInputAmount(
 	outputAmount *entities.CurrencyAmount, limitSqrtP *big.Int,
 ) (*entities.CurrencyAmount, *Pool, error) {
+	inputAmount, newPoolState, _, err := p.getInputAmount(outputAmount, limitSqrtP)
+	return inputAmount, newPoolState, err
+}
+
+// getInputAmount is GetInputAmount that also returns the number of initialized ticks crossed by the swap
+func (p *Pool) getInputAmount(
+	outputAmount *entities.CurrencyAmount, limitSqrtP *big.Int,
+) (*entities.CurrencyAmount, *Pool, int, error) {
 	if !(outputAmount.Currency.IsToken() && p.InvolvesToken(outputAmount.Currency.Wrapped())) {
-		return nil, nil, ErrTokenNotInvolved
+		return nil, nil, 0, ErrTokenNotInvolved
 	}
 	zeroForOne := outputAmount.Currency.Equal(p.Token1)
-	returnedAmount, baseL, reinvestL, sqrtP, currentTick, nextTick, err := p.swap(
+	returnedAmount, baseL, reinvestL, sqrtP, currentTick, nextTick, ticksCrossed, err := p.swap(
 		zeroForOne,
 		new(big.Int).Mul(outputAmount.Quotient(), constants.NegativeOne),
 		limitSqrtP,
+		nil,
 	)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, 0, err
 	}
 
 	var inputToken *entities.Token
@@ -254,7 +288,17 @@ func (p *Pool) GetInputAmount(
 
 	newPoolState := p._updatePoolData(baseL, reinvestL, sqrtP, currentTick, nextTick)
 
-	return entities.FromRawAmount(inputToken, returnedAmount), newPoolState, nil
+	return entities.FromRawAmount(inputToken, returnedAmount), newPoolState, ticksCrossed, nil
+}
+
+func (p *Pool) ticksCrossedOutput(inputAmount *entities.CurrencyAmount) (*entities.CurrencyAmount, int, error) {
+	outputAmount, _, ticksCrossed, err := p.getOutputAmount(inputAmount, nil)
+	return outputAmount, ticksCrossed, err
+}
+
+func (p *Pool) ticksCrossedInput(outputAmount *entities.CurrencyAmount) (*entities.CurrencyAmount, int, error) {
+	inputAmount, _, ticksCrossed, err := p.getInputAmount(outputAmount, nil)
+	return inputAmount, ticksCrossed, err
 }
 
 // Source: https://github.com/KyberNetwork/ks-elastic-sc-v2/blob/3ba84353cbd88f30f222bb9c673e242a2e46fd12/contracts/PoolTicksState.sol#L121-L147C4
@@ -282,13 +326,15 @@ func (p *Pool) _getInitialSwapData(willUpTick bool) (
  * @param zeroForOne Whether the amount in is token0 or token1
  * @param amountSpecified The amount of the swap, which implicitly configures the swap as exact input (positive), or exact output (negative)
  * @param sqrtPriceLimitX96 The Q64.96 sqrt price limit. If zero for one, the price cannot be less than this value after the swap. If one for zero, the price cannot be greater than this value after the swap
+ * @param trace Optional, collects the amount used and the segments of the swap
  * @returns returnedAmount
  * @returns sqrtRatioX96
  * @returns liquidity
  * @returns tickCurrent
+ * @returns ticksCrossed the number of initialized ticks crossed
  */
-func (p *Pool) swap(isToken0 bool, swapQty *big.Int, limitSqrtP *big.Int) (
-	*big.Int, *big.Int, *big.Int, *big.Int, int, int, error,
+func (p *Pool) swap(isToken0 bool, swapQty *big.Int, limitSqrtP *big.Int, trace *swapTrace) (
+	*big.Int, *big.Int, *big.Int, *big.Int, int, int, int, error,
 ) {
 	var swapData SwapData
 	swapData.specifiedAmount = swapQty
@@ -315,15 +361,19 @@ func (p *Pool) swap(isToken0 bool, swapQty *big.Int, limitSqrtP *big.Int) (
 
 	if willUpTick {
 		if limitSqrtP.Cmp(p.SqrtP) < 0 || limitSqrtP.Cmp(utils.MaxSqrtRatio) > 0 {
-			return nil, nil, nil, nil, 0, 0, ErrBadLimitSqrtP
+			return nil, nil, nil, nil, 0, 0, 0, ErrBadLimitSqrtP
 		}
 	} else {
 		if limitSqrtP.Cmp(p.SqrtP) > 0 || limitSqrtP.Cmp(utils.MinSqrtRatio) < 0 {
-			return nil, nil, nil, nil, 0, 0, ErrBadLimitSqrtP
+			return nil, nil, nil, nil, 0, 0, 0, ErrBadLimitSqrtP
 		}
 	}
 
-	var err error
+	var (
+		ticksCrossed int
+		err          error
+	)
+	segmentTick, segmentReinvestL := swapData.currentTick, swapData.reinvestL
 
 	// continue swapping while specified input/output isn't satisfied or price limit not reached
 	for swapData.specifiedAmount.Cmp(constants.Zero) != 0 && swapData.sqrtP.Cmp(limitSqrtP) != 0 {
@@ -339,9 +389,9 @@ func (p *Pool) swap(isToken0 bool, swapQty *big.Int, limitSqrtP *big.Int) (
 		}
 
 		swapData.startSqrtP = swapData.sqrtP
-		swapData.nextSqrtP, err = utils.GetSqrtRatioAtTick(tempNextTick)
+		swapData.nextSqrtP, err = p.Math.GetSqrtRatioAtTick(tempNextTick)
 		if err != nil {
-			return nil, nil, nil, nil, 0, 0, err
+			return nil, nil, nil, nil, 0, 0, 0, err
 		}
 
 		targetSqrtP := swapData.nextSqrtP
@@ -351,7 +401,7 @@ func (p *Pool) swap(isToken0 bool, swapQty *big.Int, limitSqrtP *big.Int) (
 		}
 
 		var usedAmount, returnedAmount, deltaL *big.Int
-		usedAmount, returnedAmount, deltaL, swapData.sqrtP, err = utils.ComputeSwapStep(
+		usedAmount, returnedAmount, deltaL, swapData.sqrtP, err = p.Math.ComputeSwapStep(
 			new(big.Int).Add(swapData.baseL, swapData.reinvestL),
 			swapData.sqrtP,
 			targetSqrtP,
@@ -361,18 +411,21 @@ func (p *Pool) swap(isToken0 bool, swapQty *big.Int, limitSqrtP *big.Int) (
 			isToken0,
 		)
 		if err != nil {
-			return nil, nil, nil, nil, 0, 0, err
+			return nil, nil, nil, nil, 0, 0, 0, err
 		}
 
 		swapData.specifiedAmount = new(big.Int).Sub(swapData.specifiedAmount, usedAmount)
 		swapData.returnedAmount = new(big.Int).Add(swapData.returnedAmount, returnedAmount)
-		swapData.reinvestL = new(big.Int).Add(swapData.reinvestL, deltaL)
+		swapData.reinvestL, err = p.Math.ApplyLiquidityDelta(swapData.reinvestL, deltaL, true)
+		if err != nil {
+			return nil, nil, nil, nil, 0, 0, 0, err
+		}
 
 		if swapData.sqrtP.Cmp(swapData.nextSqrtP) != 0 {
 			if swapData.sqrtP != swapData.startSqrtP {
-				swapData.currentTick, err = utils.GetTickAtSqrtRatio(swapData.sqrtP)
+				swapData.currentTick, err = p.Math.GetTickAtSqrtRatio(swapData.sqrtP)
 				if err != nil {
-					return nil, nil, nil, nil, 0, 0, err
+					return nil, nil, nil, nil, 0, 0, 0, err
 				}
 			}
 			break
@@ -390,14 +443,26 @@ func (p *Pool) swap(isToken0 bool, swapQty *big.Int, limitSqrtP *big.Int) (
 			continue
 		}
 
-		swapData.baseL, swapData.nextTick = p._updateLiquidityAndCrossTick(
+		if trace != nil {
+			trace.addSegment(segmentTick, swapData.baseL, segmentReinvestL, swapData.reinvestL)
+		}
+		swapData.baseL, swapData.nextTick, err = p._updateLiquidityAndCrossTick(
 			swapData.nextTick,
 			swapData.baseL,
 			willUpTick,
 		)
+		if err != nil {
+			return nil, nil, nil, nil, 0, 0, 0, err
+		}
+		ticksCrossed++
+		segmentTick, segmentReinvestL = swapData.currentTick, swapData.reinvestL
 	}
 
-	return swapData.returnedAmount, swapData.baseL, swapData.reinvestL, swapData.sqrtP, swapData.currentTick, swapData.nextTick, nil
+	if trace != nil {
+		trace.addSegment(segmentTick, swapData.baseL, segmentReinvestL, swapData.reinvestL)
+		trace.usedAmount = new(big.Int).Sub(swapQty, swapData.specifiedAmount)
+	}
+	return swapData.returnedAmount, swapData.baseL, swapData.reinvestL, swapData.sqrtP, swapData.currentTick, swapData.nextTick, ticksCrossed, nil
 }
 
 // Source: https://github.com/KyberNetwork/ks-elastic-sc-v2/blob/3ba84353cbd88f30f222bb9c673e242a2e46fd12/contracts/PoolTicksState.sol#L78-L103
@@ -405,7 +470,7 @@ func (p *Pool) _updateLiquidityAndCrossTick(
 	nextTick int,
 	currentLiquidity *big.Int,
 	willUpTick bool,
-) (newLiquidity *big.Int, newNextTick int) {
+) (newLiquidity *big.Int, newNextTick int, err error) {
 	liquidityNet := p.Ticks[nextTick].LiquidityNet
 
 	if willUpTick {
@@ -416,7 +481,7 @@ func (p *Pool) _updateLiquidityAndCrossTick(
 	}
 
 	if liquidityNet == nil {
-		return constants.Zero, newNextTick
+		return constants.Zero, newNextTick, nil
 	}
 
 	var liquidityDelta *big.Int
@@ -426,9 +491,12 @@ func (p *Pool) _updateLiquidityAndCrossTick(
 		liquidityDelta = new(big.Int).Mul(liquidityNet, constants.NegativeOne)
 	}
 
-	newLiquidity = utils.ApplyLiquidityDelta(currentLiquidity, liquidityDelta, liquidityNet.Cmp(constants.Zero) >= 0)
+	newLiquidity, err = p.Math.ApplyLiquidityDelta(currentLiquidity, liquidityDelta, liquidityNet.Cmp(constants.Zero) >= 0)
+	if err != nil {
+		return nil, 0, err
+	}
 
-	return newLiquidity, newNextTick
+	return newLiquidity, newNextTick, nil
 }
 
 // In the contract, this function will mutate the pool state directly
@@ -449,6 +517,7 @@ func (p *Pool) _updatePoolData(
 	newPoolState.ReinvestL = reinvestL
 	newPoolState.SqrtP = sqrtP
 	newPoolState.CurrentTick = currentTick
+	newPoolState.Math = p.Math
 	if nextTick > currentTick {
 		newPoolState.NearestCurrentTick = p.InitializedTicks[nextTick].Previous
 	} else {
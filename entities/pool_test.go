@@ -838,3 +838,72 @@ func TestGetInputAmount(t *testing.T) {
 	assert.True(t, inputAmount.Currency.Equal(DAI))
 	assert.Equal(t, inputAmount.Quotient(), big.NewInt(98))
 }
+
+func TestPoolUint256Math(t *testing.T) {
+	pools := []func() *Pool{
+		newTestPoolFee0008, newTestPoolFee001, newTestPoolFee002, newTestPoolFee004, newTestPoolFee01,
+		newTestPoolFee025, newTestPoolFee03, newTestPoolFee1, newTestPoolFee2, newTestPoolFee5,
+	}
+	amounts := []*entities.CurrencyAmount{
+		entities.FromRawAmount(USDC, big.NewInt(1000000)),
+		entities.FromRawAmount(DAI, big.NewInt(24295310180196433)),
+		entities.FromRawAmount(USDC, big.NewInt(98)),
+		entities.FromRawAmount(DAI, new(big.Int).Mul(OneEther, big.NewInt(1000))),
+	}
+	for _, newPool := range pools {
+		for _, mode := range []utils.MathMode{utils.MathUint256, utils.MathStrict} {
+			pool, fast := newPool(), newPool()
+			fast.Math = mode
+			for _, amount := range amounts {
+				expected, expectedPool, err := pool.GetOutputAmount(amount, nil)
+				assert.NoError(t, err)
+				actual, actualPool, err := fast.GetOutputAmount(amount, nil)
+				assert.NoError(t, err)
+				assert.Equal(t, expected.Quotient(), actual.Quotient())
+				assert.Equal(t, expectedPool.SqrtP, actualPool.SqrtP)
+				assert.Equal(t, expectedPool.ReinvestL, actualPool.ReinvestL)
+				assert.Equal(t, expectedPool.CurrentTick, actualPool.CurrentTick)
+				assert.Equal(t, mode, actualPool.Math)
+
+				expected, _, err = pool.GetInputAmount(amount, nil)
+				assert.NoError(t, err)
+				actual, _, err = fast.GetInputAmount(amount, nil)
+				assert.NoError(t, err)
+				assert.Equal(t, expected.Quotient(), actual.Quotient())
+			}
+		}
+	}
+}
+
+func TestPoolStrictMath(t *testing.T) {
+	amount := entities.FromRawAmount(USDC, big.NewInt(1000000))
+
+	// the reinvestment liquidity is a uint128 in the contract, the fee collected by the swap overflows it
+	pool := newTestPoolFee004()
+	pool.ReinvestL = utils.MaxUint128
+	_, _, err := pool.GetOutputAmount(amount, nil)
+	assert.NoError(t, err)
+
+	pool.Math = utils.MathStrict
+	_, _, err = pool.GetOutputAmount(amount, nil)
+	assert.ErrorIs(t, err, utils.ErrRevertLiquidityDelta)
+	// the contract's quadratic for the fee of exact output swaps overflows first
+	_, _, err = pool.GetInputAmount(entities.FromRawAmount(DAI, big.NewInt(1000)), nil)
+	assert.ErrorIs(t, err, utils.ErrRevertArithmetic)
+}
+
+func BenchmarkPoolGetOutputAmount(b *testing.B) {
+	amount := entities.FromRawAmount(USDC, big.NewInt(1000000))
+	for _, mode := range []struct {
+		name string
+		math utils.MathMode
+	}{{"BigInt", utils.MathBigInt}, {"Uint256", utils.MathUint256}} {
+		pool := newTestPoolFee004()
+		pool.Math = mode.math
+		b.Run(mode.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _, _ = pool.GetOutputAmount(amount, nil)
+			}
+		})
+	}
+}
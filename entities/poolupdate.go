@@ -0,0 +1,174 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+var ErrLiquidityUnderflow = errors.New("liquidity removed exceeds the liquidity of the pool")
+
+// The part of a swap between two initialized ticks, over which the base liquidity stays the same
+type SwapSegment struct {
+	Tick      int      // The current tick when the segment starts, the positions with TickLower <= Tick < TickUpper are in range
+	BaseL     *big.Int // The base liquidity in range over the segment
+	ReinvestL *big.Int // The reinvestment liquidity at the start of the segment
+	FeeL      *big.Int // The reinvestment liquidity the swap fees of the segment added
+}
+
+// swapTrace collects what applying a swap to a pool needs besides what quoting it does
+type swapTrace struct {
+	usedAmount *big.Int
+	segments   []SwapSegment
+}
+
+func (t *swapTrace) addSegment(tick int, baseL, startReinvestL, endReinvestL *big.Int) {
+	t.segments = append(t.segments, SwapSegment{
+		Tick:      tick,
+		BaseL:     baseL,
+		ReinvestL: startReinvestL,
+		FeeL:      new(big.Int).Sub(endReinvestL, startReinvestL),
+	})
+}
+
+// The outcome of a swap applied to a pool
+type SwapResult struct {
+	AmountIn  *entities.CurrencyAmount
+	AmountOut *entities.CurrencyAmount
+	Pool      *Pool         // The pool after the swap, with the tokens, fee and ticks of the pool swapped in
+	Segments  []SwapSegment // The segments of the swap, in the order they were swapped through
+}
+
+/**
+ * Applies a swap to the pool. Unlike GetOutputAmount and GetInputAmount, the pool returned keeps the tokens, fee and
+ * ticks so that further swaps and liquidity changes can be applied to it, and the swap is broken down into segments for
+ * the reinvestment liquidity earned by the positions in range.
+ * @param amount the input amount of an exact input swap, or the output amount of an exact output swap
+ * @param tradeType whether the amount is the exact input or the exact output
+ * @param limitSqrtP optional, the sqrt price the swap stops at
+ */
+func (p *Pool) ApplySwap(amount *entities.CurrencyAmount, tradeType entities.TradeType, limitSqrtP *big.Int) (*SwapResult, error) {
+	if !(amount.Currency.IsToken() && p.InvolvesToken(amount.Currency.Wrapped())) {
+		return nil, ErrTokenNotInvolved
+	}
+	isToken0 := amount.Currency.Equal(p.Token0)
+	swapQty := amount.Quotient()
+	if tradeType == entities.ExactOutput {
+		swapQty = new(big.Int).Neg(swapQty)
+	}
+	var trace swapTrace
+	returnedAmount, baseL, reinvestL, sqrtP, currentTick, nextTick, _, err := p.swap(isToken0, swapQty, limitSqrtP, &trace)
+	if err != nil {
+		return nil, err
+	}
+
+	other := p.Token1
+	if !isToken0 {
+		other = p.Token0
+	}
+	result := &SwapResult{Segments: trace.segments}
+	if tradeType == entities.ExactInput {
+		result.AmountIn = entities.FromRawAmount(amount.Currency, trace.usedAmount)
+		result.AmountOut = entities.FromRawAmount(other, new(big.Int).Neg(returnedAmount))
+	} else {
+		result.AmountIn = entities.FromRawAmount(other, returnedAmount)
+		result.AmountOut = entities.FromRawAmount(amount.Currency, new(big.Int).Neg(trace.usedAmount))
+	}
+
+	result.Pool = p._updatePoolData(baseL, reinvestL, sqrtP, currentTick, nextTick)
+	result.Pool.Token0 = p.Token0
+	result.Pool.Token1 = p.Token1
+	result.Pool.Fee = p.Fee
+	result.Pool.Ticks = p.Ticks
+	result.Pool.InitializedTicks = p.InitializedTicks
+	return result, nil
+}
+
+/**
+ * Applies a change of the liquidity of a range to the pool, as minting or burning a position does
+ * @param tickLower the lower tick of the range
+ * @param tickUpper the upper tick of the range
+ * @param liquidityDelta the liquidity added, negative for the liquidity removed
+ * @returns The pool with the liquidity changed
+ */
+func (p *Pool) ApplyLiquidity(tickLower, tickUpper int, liquidityDelta *big.Int) (*Pool, error) {
+	if tickLower >= tickUpper {
+		return nil, ErrTickOrder
+	}
+	if tickLower < utils.MinTick || tickLower%p.tickSpacing() != 0 {
+		return nil, ErrTickLower
+	}
+	if tickUpper > utils.MaxTick || tickUpper%p.tickSpacing() != 0 {
+		return nil, ErrTickUpper
+	}
+
+	tickData := make(map[int]TickData, len(p.Ticks)+2)
+	for index, data := range p.Ticks {
+		tickData[index] = data
+	}
+	for _, index := range []int{tickLower, tickUpper} {
+		gross, net := big.NewInt(0), big.NewInt(0)
+		if data, ok := tickData[index]; ok {
+			gross.Set(data.LiquidityGross)
+			net.Set(data.LiquidityNet)
+		}
+		gross.Add(gross, liquidityDelta)
+		if gross.Sign() < 0 {
+			return nil, ErrLiquidityUnderflow
+		}
+		// crossing the upper tick of a range upwards removes its liquidity
+		if index == tickLower {
+			net.Add(net, liquidityDelta)
+		} else {
+			net.Sub(net, liquidityDelta)
+		}
+		if gross.Sign() == 0 {
+			delete(tickData, index)
+		} else {
+			tickData[index] = TickData{LiquidityGross: gross, LiquidityNet: net}
+		}
+	}
+
+	baseL := p.BaseL
+	if tickLower <= p.CurrentTick && p.CurrentTick < tickUpper {
+		baseL = new(big.Int).Add(baseL, liquidityDelta)
+		if baseL.Sign() < 0 {
+			return nil, ErrLiquidityUnderflow
+		}
+	}
+
+	ticks := make([]Tick, 0, len(tickData))
+	for index, data := range tickData {
+		ticks = append(ticks, Tick{Index: index, LiquidityGross: data.LiquidityGross, LiquidityNet: data.LiquidityNet})
+	}
+	sort.Slice(ticks, func(i, j int) bool {
+		return ticks[i].Index < ticks[j].Index
+	})
+	nearestCurrentTick := utils.MinTick
+	if len(ticks) > 0 {
+		var err error
+		nearestCurrentTick, err = GetNearestCurrentTick(ticks, p.CurrentTick)
+		if err != nil {
+			return nil, err
+		}
+	}
+	newTicks, initializedTicks := TransformToMap(ticks)
+
+	return &Pool{
+		Token0:             p.Token0,
+		Token1:             p.Token1,
+		Fee:                p.Fee,
+		SqrtP:              p.SqrtP,
+		BaseL:              baseL,
+		ReinvestL:          p.ReinvestL,
+		CurrentTick:        p.CurrentTick,
+		NearestCurrentTick: nearestCurrentTick,
+		Ticks:              newTicks,
+		InitializedTicks:   initializedTicks,
+		Math:               p.Math,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+)
+
+func TestApplyLiquidity(t *testing.T) {
+	pool := v2StylePool(token0, token1, entities.FromRawAmount(token0, OneEther), entities.FromRawAmount(token1, OneEther), constants.Fee1)
+
+	minted, err := pool.ApplyLiquidity(-400, 400, OneEther)
+	assert.NoError(t, err)
+	assert.Equal(t, new(big.Int).Mul(OneEther, big.NewInt(2)), minted.BaseL)
+	assert.Equal(t, -400, minted.NearestCurrentTick)
+	assert.Equal(t, OneEther, minted.Ticks[-400].LiquidityNet)
+	assert.Equal(t, new(big.Int).Neg(OneEther), minted.Ticks[400].LiquidityNet)
+	assert.Equal(t, 400, minted.InitializedTicks[-400].Next)
+	// the pool applied to is left as it was
+	assert.Equal(t, OneEther, pool.BaseL)
+	assert.Equal(t, 2, len(pool.Ticks))
+
+	// out of range liquidity does not change the base liquidity
+	above, err := minted.ApplyLiquidity(600, 1000, OneEther)
+	assert.NoError(t, err)
+	assert.Equal(t, minted.BaseL, above.BaseL)
+	assert.Equal(t, 1000, above.InitializedTicks[600].Next)
+
+	burned, err := minted.ApplyLiquidity(-400, 400, new(big.Int).Neg(OneEther))
+	assert.NoError(t, err)
+	assert.Equal(t, pool.BaseL, burned.BaseL)
+	assert.Equal(t, pool.Ticks, burned.Ticks)
+	assert.Equal(t, pool.InitializedTicks, burned.InitializedTicks)
+	assert.Equal(t, pool.NearestCurrentTick, burned.NearestCurrentTick)
+
+	_, err = minted.ApplyLiquidity(-400, 400, new(big.Int).Neg(new(big.Int).Mul(OneEther, big.NewInt(2))))
+	assert.ErrorIs(t, err, ErrLiquidityUnderflow)
+	_, err = pool.ApplyLiquidity(400, -400, OneEther)
+	assert.ErrorIs(t, err, ErrTickOrder)
+	_, err = pool.ApplyLiquidity(-401, 400, OneEther)
+	assert.ErrorIs(t, err, ErrTickLower)
+}
+
+func TestApplySwap(t *testing.T) {
+	pool := v2StylePool(token0, token1, entities.FromRawAmount(token0, OneEther), entities.FromRawAmount(token1, OneEther), constants.Fee1)
+	pool, err := pool.ApplyLiquidity(-400, 400, OneEther)
+	assert.NoError(t, err)
+
+	// swaps the same as a quote
+	amountIn := entities.FromRawAmount(token0, big.NewInt(1e16))
+	result, err := pool.ApplySwap(amountIn, entities.ExactInput, nil)
+	assert.NoError(t, err)
+	outputAmount, quoted, err := pool.GetOutputAmount(amountIn, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, amountIn, result.AmountIn)
+	assert.Equal(t, outputAmount, result.AmountOut)
+	assert.Equal(t, quoted.SqrtP, result.Pool.SqrtP)
+	assert.Equal(t, pool.Ticks, result.Pool.Ticks)
+	assert.Equal(t, token0, result.Pool.Token0)
+	assert.Equal(t, 1, len(result.Segments))
+	assert.Equal(t, new(big.Int).Sub(result.Pool.ReinvestL, pool.ReinvestL), result.Segments[0].FeeL)
+	assert.Positive(t, result.Segments[0].FeeL.Sign())
+
+	// a swap through the lower tick of the range is broken down at the tick
+	result, err = pool.ApplySwap(entities.FromRawAmount(token0, big.NewInt(5e16)), entities.ExactInput, nil)
+	assert.NoError(t, err)
+	assert.Less(t, result.Pool.CurrentTick, -400)
+	assert.Equal(t, OneEther, result.Pool.BaseL)
+	assert.Equal(t, 2, len(result.Segments))
+	assert.Equal(t, pool.CurrentTick, result.Segments[0].Tick)
+	assert.Equal(t, pool.BaseL, result.Segments[0].BaseL)
+	assert.Equal(t, -401, result.Segments[1].Tick)
+	assert.Equal(t, OneEther, result.Segments[1].BaseL)
+	assert.Equal(t, new(big.Int).Add(result.Segments[0].ReinvestL, result.Segments[0].FeeL), result.Segments[1].ReinvestL)
+
+	// the swapped pool can be swapped in again
+	outputAmount = entities.FromRawAmount(token0, big.NewInt(1e15))
+	result, err = result.Pool.ApplySwap(outputAmount, entities.ExactOutput, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, outputAmount, result.AmountOut)
+	assert.Equal(t, token1, result.AmountIn.Currency)
+
+	_, err = pool.ApplySwap(entities.FromRawAmount(token2, big.NewInt(1)), entities.ExactInput, nil)
+	assert.ErrorIs(t, err, ErrTokenNotInvolved)
+}
@@ -0,0 +1,197 @@
+package entities
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+)
+
+var (
+	ErrNoQuoteForAmount = errors.New("no route could be quoted for amount")
+)
+
+// QuoteRequestOf is a single swap simulation to run through a route
+type QuoteRequestOf[P SwapPool[P]] struct {
+	Route     *RouteOf[P]
+	Amount    *entities.CurrencyAmount // The amount specified, either input or output, depending on TradeType
+	TradeType entities.TradeType
+}
+
+// QuoteRequest is a swap simulation through a route of Elastic pools
+type QuoteRequest = QuoteRequestOf[*Pool]
+
+// QuoteResultOf is the outcome of a QuoteRequestOf. Exactly one of Trade and Err is set.
+type QuoteResultOf[P SwapPool[P]] struct {
+	Trade *TradeOf[P]
+	Err   error
+}
+
+// QuoteResult is the outcome of a QuoteRequest
+type QuoteResult = QuoteResultOf[*Pool]
+
+/**
+ * QuoteEngineOf runs swap simulations on a bounded pool of goroutines.
+ *
+ * Pools and routes are shared between workers as read-only snapshots: simulating a swap never mutates a Pool, it
+ * returns the updated state as a new Pool instead. Callers must not modify pools while a quote is in progress.
+ *
+ * The context is checked before each simulation starts, a simulation that is already running is not interrupted.
+ */
+type QuoteEngineOf[P SwapPool[P]] struct {
+	workers int
+}
+
+// QuoteEngine quotes routes of Elastic pools
+type QuoteEngine = QuoteEngineOf[*Pool]
+
+/**
+ * Constructs a quote engine
+ * @param workers the maximum number of simulations running at once, defaults to GOMAXPROCS when not positive
+ */
+func NewQuoteEngine(workers int) *QuoteEngine {
+	return NewQuoteEngineOf[*Pool](workers)
+}
+
+// NewQuoteEngineOf is NewQuoteEngine for routes through pools of any protocol, e.g. ClassicPool or AnyPool
+func NewQuoteEngineOf[P SwapPool[P]](workers int) *QuoteEngineOf[P] {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &QuoteEngineOf[P]{workers: workers}
+}
+
+/**
+ * Simulates all requests concurrently
+ * @param ctx the context bounding the whole batch
+ * @param requests the swaps to simulate
+ * @returns One result per request, in the same order as the requests. If the context is done before every request
+ * was simulated, the remaining results carry the context error, which is also returned.
+ */
+func (e *QuoteEngineOf[P]) Quote(ctx context.Context, requests []*QuoteRequestOf[P]) ([]*QuoteResultOf[P], error) {
+	results := make([]*QuoteResultOf[P], len(requests))
+	jobs := make(chan int)
+
+	workers := e.workers
+	if workers > len(requests) {
+		workers = len(requests)
+	}
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				req := requests[i]
+				trade, err := FromRoute(req.Route, req.Amount, req.TradeType)
+				results[i] = &QuoteResultOf[P]{Trade: trade, Err: err}
+			}
+		}()
+	}
+
+dispatch:
+	for i := range requests {
+		select {
+		case <-ctx.Done():
+			break dispatch
+		case jobs <- i:
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		for i, result := range results {
+			if result == nil {
+				results[i] = &QuoteResultOf[P]{Err: err}
+			}
+		}
+		return results, err
+	}
+	return results, nil
+}
+
+/**
+ * Quotes every amount of a ladder over every route
+ * @param ctx the context bounding the whole ladder
+ * @param routes the routes to quote
+ * @param amounts the amounts specified, either input or output, depending on tradeType
+ * @param tradeType whether the amounts are exact input or exact output
+ * @returns The results indexed by amount then route
+ */
+func (e *QuoteEngineOf[P]) QuoteLadder(ctx context.Context, routes []*RouteOf[P], amounts []*entities.CurrencyAmount, tradeType entities.TradeType) ([][]*QuoteResultOf[P], error) {
+	requests := make([]*QuoteRequestOf[P], 0, len(routes)*len(amounts))
+	for _, amount := range amounts {
+		for _, route := range routes {
+			requests = append(requests, &QuoteRequestOf[P]{Route: route, Amount: amount, TradeType: tradeType})
+		}
+	}
+	results, err := e.Quote(ctx, requests)
+
+	ladder := make([][]*QuoteResultOf[P], len(amounts))
+	for i := range amounts {
+		ladder[i] = results[i*len(routes) : (i+1)*len(routes)]
+	}
+	return ladder, err
+}
+
+/**
+ * Quotes every amount of a ladder over every route and ranks the trades of each amount
+ * @param ctx the context bounding the whole ladder
+ * @param routes the routes to quote
+ * @param amounts the amounts specified, either input or output, depending on tradeType
+ * @param tradeType whether the amounts are exact input or exact output
+ * @param opts how many trades to keep per amount and the optional gas model, MaxHops is ignored
+ * @returns The best trades per amount, best first. Routes that fail to simulate are skipped, and an amount that no
+ * route could be quoted for fails the whole ladder.
+ */
+func (e *QuoteEngineOf[P]) BestTrades(ctx context.Context, routes []*RouteOf[P], amounts []*entities.CurrencyAmount, tradeType entities.TradeType, opts *BestTradeOptions) ([][]*TradeOf[P], error) {
+	if len(routes) == 0 {
+		return nil, ErrNoPools
+	}
+	if opts == nil {
+		opts = &BestTradeOptions{MaxNumResults: 3, MaxHops: 3}
+	}
+	for _, route := range routes {
+		if !route.Input.Wrapped().Equal(routes[0].Input.Wrapped()) {
+			return nil, ErrInputCurrencyMismatch
+		}
+		if !route.Output.Wrapped().Equal(routes[0].Output.Wrapped()) {
+			return nil, ErrOutputCurrencyMismatch
+		}
+	}
+	if opts.GasModel != nil {
+		if err := opts.GasModel.validate(routes[0].Input, routes[0].Output, tradeType); err != nil {
+			return nil, err
+		}
+	}
+
+	ladder, err := e.QuoteLadder(ctx, routes, amounts, tradeType)
+	if err != nil {
+		return nil, err
+	}
+
+	best := make([][]*TradeOf[P], len(amounts))
+	for i, results := range ladder {
+		var lastErr error
+		for _, result := range results {
+			if result.Err != nil {
+				lastErr = result.Err
+				continue
+			}
+			best[i], err = sortedInsert(best[i], result.Trade, opts.MaxNumResults, bestTradeComparator[P](opts))
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(best[i]) == 0 {
+			if lastErr != nil {
+				return nil, lastErr
+			}
+			return nil, ErrNoQuoteForAmount
+		}
+	}
+	return best, nil
+}
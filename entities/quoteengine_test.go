@@ -0,0 +1,146 @@
+package entities
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuoteEngineQuote(t *testing.T) {
+	r_0_1_2, _ := NewRoute([]*Pool{pool_0_1, pool_1_2}, token0, token2)
+	r_0_2, _ := NewRoute([]*Pool{pool_0_2}, token0, token2)
+
+	var requests []*QuoteRequest
+	for i := int64(1); i <= 50; i++ {
+		requests = append(requests,
+			&QuoteRequest{Route: r_0_1_2, Amount: entities.FromRawAmount(token0, big.NewInt(i*100)), TradeType: entities.ExactInput},
+			&QuoteRequest{Route: r_0_2, Amount: entities.FromRawAmount(token2, big.NewInt(i*100)), TradeType: entities.ExactOutput},
+		)
+	}
+	// a request that fails does not affect the others
+	requests = append(requests, &QuoteRequest{Route: r_0_2, Amount: entities.FromRawAmount(token1, big.NewInt(100)), TradeType: entities.ExactInput})
+
+	results, err := NewQuoteEngine(4).Quote(context.Background(), requests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(requests), len(results))
+	for i, req := range requests[:len(requests)-1] {
+		expected, err := FromRoute(req.Route, req.Amount, req.TradeType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NoError(t, results[i].Err)
+		assert.True(t, expected.InputAmount().EqualTo(results[i].Trade.InputAmount().Fraction), "input %d", i)
+		assert.True(t, expected.OutputAmount().EqualTo(results[i].Trade.OutputAmount().Fraction), "output %d", i)
+	}
+	assert.ErrorIs(t, results[len(results)-1].Err, ErrInvalidAmountForRoute)
+}
+
+func TestQuoteEngineCancellation(t *testing.T) {
+	r, _ := NewRoute([]*Pool{pool_0_1, pool_1_2}, token0, token2)
+	var requests []*QuoteRequest
+	for i := int64(1); i <= 10; i++ {
+		requests = append(requests, &QuoteRequest{Route: r, Amount: entities.FromRawAmount(token0, big.NewInt(i)), TradeType: entities.ExactInput})
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := NewQuoteEngine(2).Quote(ctx, requests)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, len(requests), len(results))
+	for _, result := range results {
+		assert.True(t, result.Err != nil || result.Trade != nil)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	_, err = NewQuoteEngine(2).BestTrades(ctx, []*Route{r}, []*entities.CurrencyAmount{entities.FromRawAmount(token0, big.NewInt(100))}, entities.ExactInput, nil)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestQuoteEngineBestTrades(t *testing.T) {
+	f, err := NewRouteFinder([]*Pool{pool_0_1, pool_0_2, pool_1_2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes, err := f.CandidateRoutes(token0, token2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	amounts := []*entities.CurrencyAmount{
+		entities.FromRawAmount(token0, big.NewInt(1)),
+		entities.FromRawAmount(token0, big.NewInt(10)),
+		entities.FromRawAmount(token0, big.NewInt(10000)),
+	}
+
+	best, err := NewQuoteEngine(0).BestTrades(context.Background(), routes, amounts, entities.ExactInput, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, len(amounts), len(best))
+	for i, amount := range amounts {
+		expected, err := f.BestTradeExactIn(amount, token2, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertSameTrades(t, expected, best[i], fmt.Sprintf("amount %d", i))
+	}
+
+	_, err = NewQuoteEngine(0).BestTrades(context.Background(), nil, amounts, entities.ExactInput, nil)
+	assert.ErrorIs(t, err, ErrNoPools)
+
+	r_0_1, _ := NewRoute([]*Pool{pool_0_1}, token0, token1)
+	_, err = NewQuoteEngine(0).BestTrades(context.Background(), append(routes, r_0_1), amounts, entities.ExactInput, nil)
+	assert.ErrorIs(t, err, ErrOutputCurrencyMismatch)
+}
+
+func BenchmarkQuoteEngineLadder(b *testing.B) {
+	pools, tokens := benchmarkPools(200)
+	f, err := NewRouteFinder(pools, nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	routes, err := f.CandidateRoutes(tokens[0], tokens[5], 3)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var amounts []*entities.CurrencyAmount
+	for i := int64(1); i <= 20; i++ {
+		amounts = append(amounts, entities.FromRawAmount(tokens[0], big.NewInt(i*1000)))
+	}
+	e := NewQuoteEngine(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.QuoteLadder(context.Background(), routes, amounts, entities.ExactInput); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestQuoteEngineClassicPools(t *testing.T) {
+	classic_1_2 := newClassicPool(t, "0xc1", entities.FromRawAmount(token1, big.NewInt(100000)), entities.FromRawAmount(token2, big.NewInt(100000)), 0, 0, ClassicBps, 0)
+	r_0_1_2, err := NewRoute([]AnyPool{AsAnyPool(pool_0_1), AsAnyPool(classic_1_2)}, token0, token2)
+	assert.NoError(t, err)
+	r_0_2, err := NewRoute([]AnyPool{AsAnyPool(pool_0_2)}, token0, token2)
+	assert.NoError(t, err)
+	amounts := []*entities.CurrencyAmount{entities.FromRawAmount(token0, big.NewInt(100)), entities.FromRawAmount(token0, big.NewInt(1000))}
+
+	best, err := NewQuoteEngineOf[AnyPool](2).BestTrades(context.Background(), []*RouteOf[AnyPool]{r_0_1_2, r_0_2}, amounts, entities.ExactInput, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, len(amounts), len(best))
+	for i, trades := range best {
+		assert.Equal(t, 2, len(trades))
+		for _, trade := range trades {
+			expected, err := FromRoute(trade.Swaps[0].Route, amounts[i], entities.ExactInput)
+			assert.NoError(t, err)
+			assert.True(t, expected.OutputAmount().EqualTo(trade.OutputAmount().Fraction))
+		}
+	}
+}
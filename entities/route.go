@@ -4,6 +4,9 @@ import (
 	"errors"
 
 	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
 )
 
 var (
@@ -12,11 +15,13 @@ var (
 	ErrInputNotInvolved  = errors.New("input token not involved in route")
 	ErrOutputNotInvolved = errors.New("output token not involved in route")
 	ErrPathNotContinuous = errors.New("path not continuous")
+	ErrMixedPathHops     = errors.New("the pools of the route identify themselves differently in a path")
+	ErrInvalidPathLength = errors.New("a path must have one more token than hops")
 )
 
-// Route represents a list of pools through which a swap can occur
-type Route struct {
-	Pools     []*Pool
+// RouteOf represents a list of pools through which a swap can occur
+type RouteOf[P SwapPool[P]] struct {
+	Pools     []P
 	TokenPath []*entities.Token
 	Input     entities.Currency
 	Output    entities.Currency
@@ -24,13 +29,16 @@ type Route struct {
 	midPrice *entities.Price
 }
 
+// Route is a route through Elastic pools
+type Route = RouteOf[*Pool]
+
 /**
  * Creates an instance of route.
  * @param pools An array of `Pool` objects, ordered by the route the swap will take
  * @param input The input token
  * @param output The output token
  */
-func NewRoute(pools []*Pool, input, output entities.Currency) (*Route, error) {
+func NewRoute[P SwapPool[P]](pools []P, input, output entities.Currency) (*RouteOf[P], error) {
 	if len(pools) == 0 {
 		return nil, ErrRouteNoPools
 	}
@@ -50,14 +58,15 @@ func NewRoute(pools []*Pool, input, output entities.Currency) (*Route, error) {
 	tokenPath := []*entities.Token{wrappedInput}
 	for i, p := range pools {
 		currentInputToken := tokenPath[i]
-		if !(currentInputToken.Equal(p.Token0) || currentInputToken.Equal(p.Token1)) {
+		token0, token1 := p.Tokens()
+		if !(currentInputToken.Equal(token0) || currentInputToken.Equal(token1)) {
 			return nil, ErrPathNotContinuous
 		}
 		var nextToken *entities.Token
-		if currentInputToken.Equal(p.Token0) {
-			nextToken = p.Token1
+		if currentInputToken.Equal(token0) {
+			nextToken = token1
 		} else {
-			nextToken = p.Token0
+			nextToken = token0
 		}
 		tokenPath = append(tokenPath, nextToken)
 	}
@@ -69,7 +78,7 @@ func NewRoute(pools []*Pool, input, output entities.Currency) (*Route, error) {
 			return nil, ErrOutputNotInvolved
 		}
 	}
-	return &Route{
+	return &RouteOf[P]{
 		Pools:     pools,
 		TokenPath: tokenPath,
 		Input:     input,
@@ -77,12 +86,12 @@ func NewRoute(pools []*Pool, input, output entities.Currency) (*Route, error) {
 	}, nil
 }
 
-func (r *Route) ChainID() uint {
+func (r *RouteOf[P]) ChainID() uint {
 	return r.Pools[0].ChainID()
 }
 
 // MidPrice Returns the mid price of the route
-func (r *Route) MidPrice() (*entities.Price, error) {
+func (r *RouteOf[P]) MidPrice() (*entities.Price, error) {
 	if r.midPrice != nil {
 		return r.midPrice, nil
 	}
@@ -90,11 +99,12 @@ func (r *Route) MidPrice() (*entities.Price, error) {
 		nextInput *entities.Token
 		price     *entities.Price
 	)
-	if r.Pools[0].Token0.Equal(r.Input) {
-		nextInput = r.Pools[0].Token1
+	token0, token1 := r.Pools[0].Tokens()
+	if token0.Equal(r.Input) {
+		nextInput = token1
 		price = r.Pools[0].Token0Price()
 	} else {
-		nextInput = r.Pools[0].Token0
+		nextInput = token0
 		price = r.Pools[0].Token1Price()
 	}
 	price, err := reducePrice(nextInput, price, r.Pools[1:])
@@ -106,17 +116,18 @@ func (r *Route) MidPrice() (*entities.Price, error) {
 }
 
 // reducePrice reduces the price of the route by the given amount
-func reducePrice(nextInput *entities.Token, price *entities.Price, pools []*Pool) (*entities.Price, error) {
+func reducePrice[P SwapPool[P]](nextInput *entities.Token, price *entities.Price, pools []P) (*entities.Price, error) {
 	var err error
 	for _, p := range pools {
-		if nextInput.Equal(p.Token0) {
-			nextInput = p.Token1
+		token0, token1 := p.Tokens()
+		if nextInput.Equal(token0) {
+			nextInput = token1
 			price, err = price.Multiply(p.Token0Price())
 			if err != nil {
 				return nil, err
 			}
 		} else {
-			nextInput = p.Token0
+			nextInput = token0
 			price, err = price.Multiply(p.Token1Price())
 			if err != nil {
 				return nil, err
@@ -125,3 +136,55 @@ func reducePrice(nextInput *entities.Token, price *entities.Price, pools []*Pool
 	}
 	return price, nil
 }
+
+/**
+ * Encodes the route as a packed swap path, the tokens of the path separated by the hop of the pool between them. A
+ * router decodes the path with hops of a single size, so the pools of a route mixing protocols, such as Elastic fees
+ * and Classic pool addresses, can not be encoded together.
+ * @param exactOutput whether to encode the path from the output to the input, as exact output swaps take it
+ */
+func (r *RouteOf[P]) EncodePath(exactOutput bool) ([]byte, error) {
+	tokens := make([]common.Address, len(r.TokenPath))
+	for i, token := range r.TokenPath {
+		tokens[i] = token.Address
+	}
+	hops := make([][]byte, len(r.Pools))
+	for i, p := range r.Pools {
+		hops[i] = p.PathHop()
+	}
+	return EncodePath(tokens, hops, exactOutput)
+}
+
+/**
+ * Tight packs the tokens of a swap path, each separated from the next by the hop of the pool between them
+ * @param tokens the tokens of the path, in the order of the swap
+ * @param hops the hops identifying the pools between the tokens, all of the same size
+ * @param exactOutput whether to encode the path from the output to the input, as exact output swaps take it
+ */
+func EncodePath(tokens []common.Address, hops [][]byte, exactOutput bool) ([]byte, error) {
+	if len(hops) == 0 || len(tokens) != len(hops)+1 {
+		return nil, ErrInvalidPathLength
+	}
+	segments := [][]byte{tokens[0].Bytes()}
+	for i, hop := range hops {
+		if len(hop) != len(hops[0]) {
+			return nil, ErrMixedPathHops
+		}
+		segments = append(segments, hop, tokens[i+1].Bytes())
+	}
+	if exactOutput {
+		for i, j := 0, len(segments)-1; i < j; i, j = i+1, j-1 {
+			segments[i], segments[j] = segments[j], segments[i]
+		}
+	}
+	var path []byte
+	for _, segment := range segments {
+		path = append(path, segment...)
+	}
+	return path, nil
+}
+
+// FeePathHop returns the fee of an Elastic pool as the uint24 identifying the pool in a packed swap path
+func FeePathHop(fee constants.FeeAmount) []byte {
+	return []byte{byte(fee >> 16), byte(fee >> 8), byte(fee)}
+}
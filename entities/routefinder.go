@@ -0,0 +1,274 @@
+package entities
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+)
+
+var (
+	ErrSameCurrency = errors.New("input and output currency are the same")
+)
+
+// RouteFinderOptions configures how a RouteFinder builds its token graph
+type RouteFinderOptions struct {
+	MinLiquidity *big.Int // pools whose base plus reinvestment liquidity is below this value are pruned from the graph
+}
+
+type tokenKey struct {
+	chainID uint
+	address common.Address
+}
+
+type poolKey struct {
+	token0 common.Address
+	token1 common.Address
+	fee    constants.FeeAmount
+}
+
+type routeKey struct {
+	from    tokenKey
+	to      tokenKey
+	maxHops int
+}
+
+func newTokenKey(token *entities.Token) tokenKey {
+	return tokenKey{chainID: token.ChainId(), address: token.Address}
+}
+
+/**
+ * RouteFinder indexes a set of pools by token once and enumerates the candidate routes between two tokens.
+ *
+ * Routes never use the same pool twice and never revisit a token, so cycles are not explored. Candidate routes are
+ * cached per token pair and hop limit, which makes quoting many amounts over the same pool set cheap.
+ *
+ * A RouteFinder is safe for concurrent use.
+ */
+type RouteFinder struct {
+	pools     []*Pool
+	adjacency map[tokenKey][]int
+
+	mu    sync.RWMutex
+	cache map[routeKey][][]*Pool
+}
+
+/**
+ * Constructs a route finder over the given pools
+ * @param pools the pools to consider in finding routes, duplicates are ignored
+ * @param opts optional pruning configuration
+ */
+func NewRouteFinder(pools []*Pool, opts *RouteFinderOptions) (*RouteFinder, error) {
+	if len(pools) == 0 {
+		return nil, ErrNoPools
+	}
+	var minLiquidity *big.Int
+	if opts != nil {
+		minLiquidity = opts.MinLiquidity
+	}
+
+	f := &RouteFinder{
+		adjacency: make(map[tokenKey][]int),
+		cache:     make(map[routeKey][][]*Pool),
+	}
+	seen := make(map[poolKey]bool, len(pools))
+	for _, pool := range pools {
+		key := poolKey{token0: pool.Token0.Address, token1: pool.Token1.Address, fee: pool.Fee}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		if minLiquidity != nil && new(big.Int).Add(pool.BaseL, pool.ReinvestL).Cmp(minLiquidity) < 0 {
+			continue
+		}
+
+		i := len(f.pools)
+		f.pools = append(f.pools, pool)
+		f.adjacency[newTokenKey(pool.Token0)] = append(f.adjacency[newTokenKey(pool.Token0)], i)
+		f.adjacency[newTokenKey(pool.Token1)] = append(f.adjacency[newTokenKey(pool.Token1)], i)
+	}
+	return f, nil
+}
+
+// Pools returns the pools indexed by the route finder, after deduplication and pruning
+func (f *RouteFinder) Pools() []*Pool {
+	return f.pools
+}
+
+/**
+ * Returns all routes from currencyIn to currencyOut making at most maxHops hops
+ * @param currencyIn the input currency
+ * @param currencyOut the output currency
+ * @param maxHops maximum number of hops a route can make, e.g. 1 hop goes through a single pool
+ * @returns The candidate routes, in the order they were discovered
+ */
+func (f *RouteFinder) CandidateRoutes(currencyIn, currencyOut entities.Currency, maxHops int) ([]*Route, error) {
+	paths, err := f.paths(currencyIn.Wrapped(), currencyOut.Wrapped(), maxHops)
+	if err != nil {
+		return nil, err
+	}
+	routes := make([]*Route, 0, len(paths))
+	for _, path := range paths {
+		r, err := NewRoute(path, currencyIn, currencyOut)
+		if err != nil {
+			return nil, err
+		}
+		routes = append(routes, r)
+	}
+	return routes, nil
+}
+
+/**
+ * Given a fixed amount in, returns the top `maxNumResults` trades that go from an input token amount to an output
+ * token, making at most `maxHops` hops. It returns the same trades as BestTradeExactIn over the same pools, except
+ * that routes revisiting a token are not considered.
+ * @param currencyAmountIn exact amount of input currency to spend
+ * @param currencyOut the desired currency out
+ * @param opts maximum number of results and hops, defaults to 3 and 3
+ * @returns The exact in trades, best first
+ */
+func (f *RouteFinder) BestTradeExactIn(currencyAmountIn *entities.CurrencyAmount, currencyOut entities.Currency, opts *BestTradeOptions) ([]*Trade, error) {
+	if opts == nil {
+		opts = &BestTradeOptions{MaxNumResults: 3, MaxHops: 3}
+	}
+	if opts.GasModel != nil {
+		if err := opts.GasModel.validate(currencyAmountIn.Currency, currencyOut, entities.ExactInput); err != nil {
+			return nil, err
+		}
+	}
+	paths, err := f.paths(currencyAmountIn.Currency.Wrapped(), currencyOut.Wrapped(), opts.MaxHops)
+	if err != nil {
+		return nil, err
+	}
+
+	var bestTrades []*Trade
+	for _, path := range paths {
+		r, err := NewRoute(path, currencyAmountIn.Currency, currencyOut)
+		if err != nil {
+			return nil, err
+		}
+		// a route through a pool that can not simulate the swap, short of liquidity or tick data, is not a candidate
+		trade, err := FromRoute(r, currencyAmountIn, entities.ExactInput)
+		if err != nil {
+			continue
+		}
+		bestTrades, err = sortedInsert(bestTrades, trade, opts.MaxNumResults, bestTradeComparator[*Pool](opts))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bestTrades, nil
+}
+
+/**
+ * Similar to BestTradeExactIn but instead targets a fixed output amount
+ * @param currencyIn the currency to spend
+ * @param currencyAmountOut the desired currency amount out
+ * @param opts maximum number of results and hops, defaults to 3 and 3
+ * @returns The exact out trades, best first
+ */
+func (f *RouteFinder) BestTradeExactOut(currencyIn entities.Currency, currencyAmountOut *entities.CurrencyAmount, opts *BestTradeOptions) ([]*Trade, error) {
+	if opts == nil {
+		opts = &BestTradeOptions{MaxNumResults: 3, MaxHops: 3}
+	}
+	if opts.GasModel != nil {
+		if err := opts.GasModel.validate(currencyIn, currencyAmountOut.Currency, entities.ExactOutput); err != nil {
+			return nil, err
+		}
+	}
+	// search backwards from the output so candidates are visited in the same order as BestTradeExactOut
+	paths, err := f.paths(currencyAmountOut.Currency.Wrapped(), currencyIn.Wrapped(), opts.MaxHops)
+	if err != nil {
+		return nil, err
+	}
+
+	var bestTrades []*Trade
+	for _, path := range paths {
+		reversed := make([]*Pool, len(path))
+		for i, pool := range path {
+			reversed[len(path)-1-i] = pool
+		}
+		r, err := NewRoute(reversed, currencyIn, currencyAmountOut.Currency)
+		if err != nil {
+			return nil, err
+		}
+		// a route through a pool that can not simulate the swap, short of liquidity or tick data, is not a candidate
+		trade, err := FromRoute(r, currencyAmountOut, entities.ExactOutput)
+		if err != nil {
+			continue
+		}
+		bestTrades, err = sortedInsert(bestTrades, trade, opts.MaxNumResults, bestTradeComparator[*Pool](opts))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return bestTrades, nil
+}
+
+// paths returns the cached pool paths from one token to another, enumerating them on a cache miss
+func (f *RouteFinder) paths(from, to *entities.Token, maxHops int) ([][]*Pool, error) {
+	if maxHops <= 0 {
+		return nil, ErrInvalidMaxHops
+	}
+	if from.Equal(to) {
+		return nil, ErrSameCurrency
+	}
+
+	key := routeKey{from: newTokenKey(from), to: newTokenKey(to), maxHops: maxHops}
+	f.mu.RLock()
+	paths, ok := f.cache[key]
+	f.mu.RUnlock()
+	if ok {
+		return paths, nil
+	}
+
+	paths = f.enumerate(key.from, key.to, maxHops)
+
+	f.mu.Lock()
+	f.cache[key] = paths
+	f.mu.Unlock()
+	return paths, nil
+}
+
+// enumerate walks the token graph depth first, visiting pools in the order they were given to the finder
+func (f *RouteFinder) enumerate(from, to tokenKey, maxHops int) [][]*Pool {
+	var (
+		paths   [][]*Pool
+		current []*Pool
+		visited = map[tokenKey]bool{from: true}
+	)
+
+	var walk func(token tokenKey, hopsLeft int)
+	walk = func(token tokenKey, hopsLeft int) {
+		for _, i := range f.adjacency[token] {
+			pool := f.pools[i]
+			next := newTokenKey(pool.Token0)
+			if next == token {
+				next = newTokenKey(pool.Token1)
+			}
+			if next == to {
+				path := make([]*Pool, len(current)+1)
+				copy(path, current)
+				path[len(current)] = pool
+				paths = append(paths, path)
+				continue
+			}
+			// never revisit a token, which also rules out reusing a pool
+			if hopsLeft <= 1 || visited[next] {
+				continue
+			}
+			visited[next] = true
+			current = append(current, pool)
+			walk(next, hopsLeft-1)
+			current = current[:len(current)-1]
+			visited[next] = false
+		}
+	}
+	walk(from, maxHops)
+	return paths
+}
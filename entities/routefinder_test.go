@@ -0,0 +1,212 @@
+package entities
+
+import (
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+func TestNewRouteFinder(t *testing.T) {
+	_, err := NewRouteFinder(nil, nil)
+	assert.ErrorIs(t, err, ErrNoPools, "throws with empty pools")
+
+	// ignores duplicate pools
+	f, err := NewRouteFinder([]*Pool{pool_0_1, pool_0_2, pool_0_1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*Pool{pool_0_1, pool_0_2}, f.Pools())
+
+	// prunes pools below the liquidity threshold
+	f, err = NewRouteFinder([]*Pool{pool_0_1, pool_1_2}, &RouteFinderOptions{MinLiquidity: big.NewInt(105000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []*Pool{pool_1_2}, f.Pools())
+}
+
+func TestCandidateRoutes(t *testing.T) {
+	f, err := NewRouteFinder([]*Pool{pool_0_1, pool_0_2, pool_1_2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = f.CandidateRoutes(token0, token2, 0)
+	assert.ErrorIs(t, err, ErrInvalidMaxHops, "throws with max hops of 0")
+	_, err = f.CandidateRoutes(token0, token0, 3)
+	assert.ErrorIs(t, err, ErrSameCurrency, "throws with same input and output")
+
+	routes, err := f.CandidateRoutes(token0, token2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(routes))
+	assert.Equal(t, []*entities.Token{token0, token1, token2}, routes[0].TokenPath)
+	assert.Equal(t, []*entities.Token{token0, token2}, routes[1].TokenPath)
+
+	// does not revisit tokens
+	pool_0_1_low := v2StylePool(token0, token1, entities.FromRawAmount(token0, big.NewInt(100000)), entities.FromRawAmount(token1, big.NewInt(100000)), constants.Fee001)
+	f, err = NewRouteFinder([]*Pool{pool_0_1, pool_0_1_low, pool_1_2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes, err = f.CandidateRoutes(token0, token2, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(routes))
+	for _, r := range routes {
+		assert.Equal(t, 2, len(r.Pools))
+	}
+
+	// works for ETHER currency input
+	f, err = NewRouteFinder([]*Pool{pool_weth_0, pool_0_1, pool_0_3, pool_1_3}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes, err = f.CandidateRoutes(Ether, token3, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 2, len(routes))
+	assert.Equal(t, Ether, routes[0].Input)
+}
+
+func TestRouteFinderMatchesBestTrade(t *testing.T) {
+	poolSets := [][]*Pool{
+		{pool_0_1, pool_0_2, pool_1_2},
+		{pool_0_1, pool_0_3, pool_1_3},
+		{pool_weth_0, pool_0_1, pool_0_3, pool_1_3},
+		{pool_0_1, pool_0_2, pool_0_3, pool_1_2, pool_1_3, pool_weth_0, pool_weth_1, pool_weth_2},
+	}
+	cases := []struct {
+		in  entities.Currency
+		out entities.Currency
+	}{
+		{token0, token2},
+		{token0, token3},
+		{Ether, token3},
+		{token3, Ether},
+		{token2, token1},
+	}
+	for i, pools := range poolSets {
+		f, err := NewRouteFinder(pools, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, c := range cases {
+			for _, maxHops := range []int{1, 2, 3} {
+				opts := &BestTradeOptions{MaxNumResults: 3, MaxHops: maxHops}
+				name := fmt.Sprintf("%d/%s->%s/%d", i, c.in.Symbol(), c.out.Symbol(), maxHops)
+
+				amountIn := entities.FromRawAmount(c.in, big.NewInt(100))
+				expected, err := BestTradeExactIn(pools, amountIn, c.out, opts, nil, nil, nil)
+				assert.NoError(t, err, name)
+				actual, err := f.BestTradeExactIn(amountIn, c.out, opts)
+				assert.NoError(t, err, name)
+				assertSameTrades(t, expected, actual, name)
+
+				amountOut := entities.FromRawAmount(c.out, big.NewInt(100))
+				expected, err = BestTradeExactOut(pools, c.in, amountOut, opts, nil, nil, nil)
+				assert.NoError(t, err, name)
+				actual, err = f.BestTradeExactOut(c.in, amountOut, opts)
+				assert.NoError(t, err, name)
+				assertSameTrades(t, expected, actual, name)
+			}
+		}
+	}
+}
+
+func TestRouteFinderSkipsFailingPools(t *testing.T) {
+	// a pool without tick data fails to simulate any swap
+	broken_0_2 := &Pool{Token0: token0, Token1: token2, Fee: constants.Fee001, SqrtP: utils.EncodeSqrtRatioX96(constants.One, constants.One), BaseL: big.NewInt(1000), ReinvestL: big.NewInt(0)}
+	f, err := NewRouteFinder([]*Pool{pool_0_1, broken_0_2, pool_0_2, pool_1_2}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trades, err := f.BestTradeExactIn(entities.FromRawAmount(token0, big.NewInt(100)), token2, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(trades))
+	for _, trade := range trades {
+		assert.NotContains(t, trade.Swaps[0].Route.Pools, broken_0_2)
+	}
+
+	trades, err = f.BestTradeExactOut(token0, entities.FromRawAmount(token2, big.NewInt(100)), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(trades))
+	for _, trade := range trades {
+		assert.NotContains(t, trade.Swaps[0].Route.Pools, broken_0_2)
+	}
+}
+
+func assertSameTrades(t *testing.T, expected, actual []*Trade, name string) {
+	if !assert.Equal(t, len(expected), len(actual), name) {
+		return
+	}
+	for i := range expected {
+		assert.Equal(t, expected[i].Swaps[0].Route.TokenPath, actual[i].Swaps[0].Route.TokenPath, name)
+		assert.True(t, expected[i].InputAmount().EqualTo(actual[i].InputAmount().Fraction), name)
+		assert.True(t, expected[i].OutputAmount().EqualTo(actual[i].OutputAmount().Fraction), name)
+	}
+}
+
+// benchmarkPools creates n pools over a ring of tokens, each token also paired with a few tokens further along
+func benchmarkPools(n int) ([]*Pool, []*entities.Token) {
+	numTokens := n/4 + 2
+	tokens := make([]*entities.Token, numTokens)
+	for i := range tokens {
+		tokens[i] = entities.NewToken(1, common.BigToAddress(big.NewInt(int64(i+1))), 18, fmt.Sprintf("t%d", i), fmt.Sprintf("token%d", i))
+	}
+	pools := make([]*Pool, 0, n)
+	for i := 0; len(pools) < n; i++ {
+		a := tokens[i%numTokens]
+		b := tokens[(i+1+i/numTokens)%numTokens]
+		if a.Equal(b) {
+			continue
+		}
+		reserve := big.NewInt(int64(1_000_000 + i*1000))
+		pools = append(pools, v2StylePool(a, b, entities.FromRawAmount(a, reserve), entities.FromRawAmount(b, big.NewInt(1_000_000)), constants.Fee004))
+	}
+	return pools, tokens
+}
+
+func BenchmarkBestTradeExactIn(b *testing.B) {
+	for _, n := range []int{20, 50} {
+		pools, tokens := benchmarkPools(n)
+		amountIn := entities.FromRawAmount(tokens[0], big.NewInt(1000))
+		b.Run(fmt.Sprintf("pools=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := BestTradeExactIn(pools, amountIn, tokens[5], nil, nil, nil, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkRouteFinderBestTradeExactIn(b *testing.B) {
+	for _, n := range []int{20, 50, 200, 1000} {
+		pools, tokens := benchmarkPools(n)
+		amountIn := entities.FromRawAmount(tokens[0], big.NewInt(1000))
+		b.Run(fmt.Sprintf("pools=%d", n), func(b *testing.B) {
+			f, err := NewRouteFinder(pools, nil)
+			if err != nil {
+				b.Fatal(err)
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := f.BestTradeExactIn(amountIn, tokens[5], nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
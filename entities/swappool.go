@@ -0,0 +1,138 @@
+package entities
+
+import (
+	"math/big"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+/**
+ * SwapPool is a pool of any AMM that routes and trades can go through. P is the type of the pool itself, returned with
+ * the state of the pool after a simulated swap. Pool implements SwapPool[*Pool].
+ */
+type SwapPool[P any] interface {
+	ChainID() uint
+	Tokens() (token0, token1 *entities.Token)
+	InvolvesToken(token *entities.Token) bool
+	Token0Price() *entities.Price // The mid price of token0 in terms of token1
+	Token1Price() *entities.Price // The mid price of token1 in terms of token0
+
+	/**
+	 * Returns the output amount of swapping the input amount, and the pool state after the swap
+	 * @param inputAmount the amount of one of the pool tokens swapped in
+	 * @param limitSqrtP the price limit of the swap, nil for none, ignored by pools without a price limit
+	 */
+	GetOutputAmount(inputAmount *entities.CurrencyAmount, limitSqrtP *big.Int) (*entities.CurrencyAmount, P, error)
+
+	/**
+	 * Returns the input amount required to receive the output amount, and the pool state after the swap
+	 * @param outputAmount the amount of one of the pool tokens swapped out
+	 * @param limitSqrtP the price limit of the swap, nil for none, ignored by pools without a price limit
+	 */
+	GetInputAmount(outputAmount *entities.CurrencyAmount, limitSqrtP *big.Int) (*entities.CurrencyAmount, P, error)
+
+	Address() (common.Address, error) // The address of the pool, pools of a trade must be distinct
+	PathHop() []byte                  // The bytes identifying the pool between its tokens in a packed swap path
+}
+
+// AnyPool is a pool of any AMM, to mix pools of several protocols in a route. Wrap pools with AsAnyPool.
+type AnyPool interface {
+	SwapPool[AnyPool]
+}
+
+// anyPool adapts a pool to AnyPool
+type anyPool[P SwapPool[P]] struct {
+	pool P
+}
+
+// AsAnyPool wraps a pool to route it along pools of other protocols
+func AsAnyPool[P SwapPool[P]](pool P) AnyPool {
+	return anyPool[P]{pool: pool}
+}
+
+/**
+ * Returns the pool wrapped by AsAnyPool
+ * @param pool the wrapped pool
+ * @returns the pool and whether it is of type P
+ */
+func PoolAs[P SwapPool[P]](pool AnyPool) (P, bool) {
+	wrapped, ok := pool.(anyPool[P])
+	return wrapped.pool, ok
+}
+
+func (a anyPool[P]) ChainID() uint {
+	return a.pool.ChainID()
+}
+
+func (a anyPool[P]) Tokens() (*entities.Token, *entities.Token) {
+	return a.pool.Tokens()
+}
+
+func (a anyPool[P]) InvolvesToken(token *entities.Token) bool {
+	return a.pool.InvolvesToken(token)
+}
+
+func (a anyPool[P]) Token0Price() *entities.Price {
+	return a.pool.Token0Price()
+}
+
+func (a anyPool[P]) Token1Price() *entities.Price {
+	return a.pool.Token1Price()
+}
+
+func (a anyPool[P]) GetOutputAmount(inputAmount *entities.CurrencyAmount, limitSqrtP *big.Int) (*entities.CurrencyAmount, AnyPool, error) {
+	outputAmount, pool, err := a.pool.GetOutputAmount(inputAmount, limitSqrtP)
+	if err != nil {
+		return nil, nil, err
+	}
+	return outputAmount, anyPool[P]{pool: pool}, nil
+}
+
+func (a anyPool[P]) GetInputAmount(outputAmount *entities.CurrencyAmount, limitSqrtP *big.Int) (*entities.CurrencyAmount, AnyPool, error) {
+	inputAmount, pool, err := a.pool.GetInputAmount(outputAmount, limitSqrtP)
+	if err != nil {
+		return nil, nil, err
+	}
+	return inputAmount, anyPool[P]{pool: pool}, nil
+}
+
+func (a anyPool[P]) Address() (common.Address, error) {
+	return a.pool.Address()
+}
+
+func (a anyPool[P]) PathHop() []byte {
+	return a.pool.PathHop()
+}
+
+func (a anyPool[P]) ticksCrossedOutput(inputAmount *entities.CurrencyAmount) (*entities.CurrencyAmount, int, error) {
+	return swapOutput(a.pool, inputAmount)
+}
+
+func (a anyPool[P]) ticksCrossedInput(outputAmount *entities.CurrencyAmount) (*entities.CurrencyAmount, int, error) {
+	return swapInput(a.pool, outputAmount)
+}
+
+// tickCrossingPool is implemented by pools whose swaps report the number of initialized ticks they cross
+type tickCrossingPool interface {
+	ticksCrossedOutput(inputAmount *entities.CurrencyAmount) (*entities.CurrencyAmount, int, error)
+	ticksCrossedInput(outputAmount *entities.CurrencyAmount) (*entities.CurrencyAmount, int, error)
+}
+
+// swapOutput returns the output amount of a swap through the pool and the initialized ticks it crosses, if counted
+func swapOutput[P SwapPool[P]](pool P, inputAmount *entities.CurrencyAmount) (*entities.CurrencyAmount, int, error) {
+	if p, ok := any(pool).(tickCrossingPool); ok {
+		return p.ticksCrossedOutput(inputAmount)
+	}
+	outputAmount, _, err := pool.GetOutputAmount(inputAmount, nil)
+	return outputAmount, 0, err
+}
+
+// swapInput returns the input amount of a swap through the pool and the initialized ticks it crosses, if counted
+func swapInput[P SwapPool[P]](pool P, outputAmount *entities.CurrencyAmount) (*entities.CurrencyAmount, int, error) {
+	if p, ok := any(pool).(tickCrossingPool); ok {
+		return p.ticksCrossedInput(outputAmount)
+	}
+	inputAmount, _, err := pool.GetInputAmount(outputAmount, nil)
+	return inputAmount, 0, err
+}
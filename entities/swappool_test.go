@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+var _ SwapPool[*Pool] = (*Pool)(nil)
+
+func TestAnyPoolTrades(t *testing.T) {
+	pools := []*Pool{pool_0_1, pool_0_2, pool_1_2}
+	anyPools := []AnyPool{AsAnyPool(pool_0_1), AsAnyPool(pool_0_2), AsAnyPool(pool_1_2)}
+	amountIn := entities.FromRawAmount(token0, big.NewInt(10000))
+	amountOut := entities.FromRawAmount(token2, big.NewInt(1000))
+
+	expected, err := BestTradeExactIn(pools, amountIn, token2, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	result, err := BestTradeExactInOf(anyPools, amountIn, token2, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, len(expected), len(result))
+	for i, trade := range result {
+		assert.True(t, trade.OutputAmount().EqualTo(expected[i].OutputAmount().Fraction))
+		assert.Equal(t, expected[i].Swaps[0].TicksCrossed, trade.Swaps[0].TicksCrossed)
+		assert.Equal(t, expected[i].Swaps[0].Route.TokenPath, trade.Swaps[0].Route.TokenPath)
+	}
+
+	expected, err = BestTradeExactOut(pools, token0, amountOut, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	result, err = BestTradeExactOutOf(anyPools, token0, amountOut, nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, len(expected), len(result))
+	for i, trade := range result {
+		assert.True(t, trade.InputAmount().EqualTo(expected[i].InputAmount().Fraction))
+	}
+
+	// the pools of a trade are unwrapped to their protocol
+	pool, ok := PoolAs[*Pool](result[0].Swaps[0].Route.Pools[0])
+	assert.True(t, ok)
+	assert.Equal(t, expected[0].Swaps[0].Route.Pools[0], pool)
+
+	// pools must be distinct across the routes of a trade
+	route, err := NewRoute([]AnyPool{anyPools[1]}, token0, token2)
+	assert.NoError(t, err)
+	_, err = FromRoutes([]*WrappedRouteOf[AnyPool]{{Amount: amountIn, Route: route}, {Amount: amountIn, Route: route}}, entities.ExactInput)
+	assert.ErrorIs(t, err, ErrDuplicatePools)
+}
+
+func TestEncodePath(t *testing.T) {
+	route, err := NewRoute([]*Pool{pool_0_1, pool_1_2}, token0, token2)
+	assert.NoError(t, err)
+	path, err := route.EncodePath(false)
+	assert.NoError(t, err)
+	assert.Equal(t, common.FromHex("0x0000000000000000000000000000000000000001"+"000028"+
+		"0000000000000000000000000000000000000002"+"000028"+
+		"0000000000000000000000000000000000000003"), path)
+	path, err = route.EncodePath(true)
+	assert.NoError(t, err)
+	assert.Equal(t, common.FromHex("0x0000000000000000000000000000000000000003"+"000028"+
+		"0000000000000000000000000000000000000002"+"000028"+
+		"0000000000000000000000000000000000000001"), path)
+
+	anyRoute, err := NewRoute([]AnyPool{AsAnyPool(pool_0_1), AsAnyPool(pool_1_2)}, token0, token2)
+	assert.NoError(t, err)
+	anyPath, err := anyRoute.EncodePath(true)
+	assert.NoError(t, err)
+	assert.Equal(t, path, anyPath)
+
+	// elastic fees and classic pool addresses can not share a path
+	classic_1_2 := newClassicPool(t, "0xc1", entities.FromRawAmount(token1, big.NewInt(100000)), entities.FromRawAmount(token2, big.NewInt(100000)), 0, 0, ClassicBps, 0)
+	mixedRoute, err := NewRoute([]AnyPool{AsAnyPool(pool_0_1), AsAnyPool(classic_1_2)}, token0, token2)
+	assert.NoError(t, err)
+	_, err = mixedRoute.EncodePath(false)
+	assert.ErrorIs(t, err, ErrMixedPathHops)
+
+	tokens := []common.Address{token0.Address, token1.Address, token2.Address}
+	hops := [][]byte{FeePathHop(pool_0_1.Fee), FeePathHop(pool_1_2.Fee)}
+	path, err = EncodePath(tokens, hops, true)
+	assert.NoError(t, err)
+	assert.Equal(t, anyPath, path)
+	_, err = EncodePath(tokens, hops[:1], false)
+	assert.ErrorIs(t, err, ErrInvalidPathLength)
+}
@@ -0,0 +1,279 @@
+package entities
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+)
+
+var (
+	ErrInvalidTokenAddress  = errors.New("invalid token address")
+	ErrInvalidTokenDecimals = errors.New("token decimals must be less than 255")
+	ErrUnknownTokenTag      = errors.New("token tag not defined by the list")
+	ErrDuplicateToken       = errors.New("duplicate token")
+	ErrTokenNotFound        = errors.New("token not found")
+	ErrAmbiguousSymbol      = errors.New("several tokens have the symbol")
+)
+
+type TokenListVersion struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+	Patch int `json:"patch"`
+}
+
+type TokenListTag struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// A token of a token list
+type TokenInfo struct {
+	ChainID  uint     `json:"chainId"`
+	Address  string   `json:"address"`
+	Decimals uint     `json:"decimals"`
+	Symbol   string   `json:"symbol"`
+	Name     string   `json:"name"`
+	LogoURI  string   `json:"logoURI,omitempty"`
+	Tags     []string `json:"tags,omitempty"` // The ids of tags defined by the list
+}
+
+// A token list, as specified by https://github.com/Uniswap/token-lists
+type TokenList struct {
+	Name      string                  `json:"name"`
+	Timestamp string                  `json:"timestamp"`
+	Version   TokenListVersion        `json:"version"`
+	Tokens    []TokenInfo             `json:"tokens"`
+	Tags      map[string]TokenListTag `json:"tags,omitempty"`
+	Keywords  []string                `json:"keywords,omitempty"`
+	LogoURI   string                  `json:"logoURI,omitempty"`
+}
+
+// ParseTokenList decodes a token list JSON document
+func ParseTokenList(data []byte) (*TokenList, error) {
+	var list TokenList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// ReadTokenListFile decodes a token list JSON file
+func ReadTokenListFile(path string) (*TokenList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTokenList(data)
+}
+
+type registeredToken struct {
+	token *entities.Token
+	tags  []string
+}
+
+/**
+ * A TokenRegistry holds the tokens of each chain by address and symbol. A token may be listed by several of the lists
+ * added, as long as they agree on its decimals and symbol, its tags are then those of all the lists.
+ */
+type TokenRegistry struct {
+	tokens   map[uint]map[common.Address]*registeredToken
+	bySymbol map[uint]map[string][]*entities.Token
+}
+
+// NewTokenRegistry constructs a registry of the tokens of the lists
+func NewTokenRegistry(lists ...*TokenList) (*TokenRegistry, error) {
+	r := &TokenRegistry{
+		tokens:   make(map[uint]map[common.Address]*registeredToken),
+		bySymbol: make(map[uint]map[string][]*entities.Token),
+	}
+	for _, list := range lists {
+		if err := r.AddList(list); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// AddList adds the tokens of a list, none of them when one of them is invalid or conflicts with a token of the registry
+func (r *TokenRegistry) AddList(list *TokenList) error {
+	listed := make(map[uint]map[common.Address]bool)
+	tokens := make([]*entities.Token, len(list.Tokens))
+	for i, info := range list.Tokens {
+		if !common.IsHexAddress(info.Address) {
+			return fmt.Errorf("%w: %q", ErrInvalidTokenAddress, info.Address)
+		}
+		address := common.HexToAddress(info.Address)
+		if info.Decimals >= 255 {
+			return fmt.Errorf("%w: %s", ErrInvalidTokenDecimals, address)
+		}
+		if list.Tags != nil {
+			for _, tag := range info.Tags {
+				if _, ok := list.Tags[tag]; !ok {
+					return fmt.Errorf("%w: %s", ErrUnknownTokenTag, tag)
+				}
+			}
+		}
+		if listed[info.ChainID] == nil {
+			listed[info.ChainID] = make(map[common.Address]bool)
+		}
+		if listed[info.ChainID][address] {
+			return fmt.Errorf("%w: %s on chain %d", ErrDuplicateToken, address, info.ChainID)
+		}
+		listed[info.ChainID][address] = true
+		tokens[i] = entities.NewToken(info.ChainID, address, info.Decimals, info.Symbol, info.Name)
+		if err := r.checkConflict(tokens[i]); err != nil {
+			return err
+		}
+	}
+	for i, token := range tokens {
+		r.add(token, list.Tokens[i].Tags)
+	}
+	return nil
+}
+
+// AddToken adds a token that is not listed
+func (r *TokenRegistry) AddToken(token *entities.Token, tags ...string) error {
+	if err := r.checkConflict(token); err != nil {
+		return err
+	}
+	r.add(token, tags)
+	return nil
+}
+
+// checkConflict returns an error if the registry has another token at the address of the token
+func (r *TokenRegistry) checkConflict(token *entities.Token) error {
+	registered, ok := r.tokens[token.ChainId()][token.Address]
+	if ok && (registered.token.Decimals() != token.Decimals() || registered.token.Symbol() != token.Symbol()) {
+		return fmt.Errorf("%w: %s on chain %d", ErrDuplicateToken, token.Address, token.ChainId())
+	}
+	return nil
+}
+
+func (r *TokenRegistry) add(token *entities.Token, tags []string) {
+	chainID := token.ChainId()
+	if r.tokens[chainID] == nil {
+		r.tokens[chainID] = make(map[common.Address]*registeredToken)
+		r.bySymbol[chainID] = make(map[string][]*entities.Token)
+	}
+	registered, ok := r.tokens[chainID][token.Address]
+	if !ok {
+		registered = &registeredToken{token: token}
+		r.tokens[chainID][token.Address] = registered
+		r.bySymbol[chainID][token.Symbol()] = append(r.bySymbol[chainID][token.Symbol()], token)
+	}
+	for _, tag := range tags {
+		if !containsTag(registered.tags, tag) {
+			registered.tags = append(registered.tags, tag)
+		}
+	}
+}
+
+// Token returns the token at the address
+func (r *TokenRegistry) Token(chainID uint, address common.Address) (*entities.Token, error) {
+	registered, ok := r.tokens[chainID][address]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s on chain %d", ErrTokenNotFound, address, chainID)
+	}
+	return registered.token, nil
+}
+
+// TokenBySymbol returns the token with the symbol, ErrAmbiguousSymbol if several tokens have it
+func (r *TokenRegistry) TokenBySymbol(chainID uint, symbol string) (*entities.Token, error) {
+	tokens := r.bySymbol[chainID][symbol]
+	switch len(tokens) {
+	case 0:
+		return nil, fmt.Errorf("%w: %s on chain %d", ErrTokenNotFound, symbol, chainID)
+	case 1:
+		return tokens[0], nil
+	}
+	return nil, fmt.Errorf("%w: %s on chain %d", ErrAmbiguousSymbol, symbol, chainID)
+}
+
+// Tags returns the tags of the token at the address
+func (r *TokenRegistry) Tags(chainID uint, address common.Address) []string {
+	if registered, ok := r.tokens[chainID][address]; ok {
+		return append([]string(nil), registered.tags...)
+	}
+	return nil
+}
+
+// Tokens returns the tokens of a chain, sorted by address
+func (r *TokenRegistry) Tokens(chainID uint) []*entities.Token {
+	tokens := make([]*entities.Token, 0, len(r.tokens[chainID]))
+	for _, registered := range r.tokens[chainID] {
+		tokens = append(tokens, registered.token)
+	}
+	sort.Slice(tokens, func(i, j int) bool {
+		return bytes.Compare(tokens[i].Address.Bytes(), tokens[j].Address.Bytes()) < 0
+	})
+	return tokens
+}
+
+// TokensWithTag returns the tokens of a chain that have the tag, sorted by address
+func (r *TokenRegistry) TokensWithTag(chainID uint, tag string) []*entities.Token {
+	var tokens []*entities.Token
+	for _, token := range r.Tokens(chainID) {
+		if containsTag(r.tokens[chainID][token.Address].tags, tag) {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// NewPoolByAddress is NewPool with the tokens resolved by their address through the registry
+func NewPoolByAddress(
+	registry *TokenRegistry,
+	chainID uint,
+	tokenA common.Address,
+	tokenB common.Address,
+	fee constants.FeeAmount,
+	sqrtRatioX96 *big.Int,
+	liquidity *big.Int,
+	reinvestLiquidity *big.Int,
+	tickCurrent int,
+	tickDataProvider TickDataProvider,
+) (*Pool, error) {
+	a, err := registry.Token(chainID, tokenA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := registry.Token(chainID, tokenB)
+	if err != nil {
+		return nil, err
+	}
+	return NewPool(a, b, fee, sqrtRatioX96, liquidity, reinvestLiquidity, tickCurrent, tickDataProvider)
+}
+
+// NewRouteByAddress is NewRoute with the input and output tokens resolved by their address through the registry, on the chain of the pools
+func NewRouteByAddress[P SwapPool[P]](registry *TokenRegistry, pools []P, input, output common.Address) (*RouteOf[P], error) {
+	if len(pools) == 0 {
+		return nil, ErrRouteNoPools
+	}
+	inputToken, err := registry.Token(pools[0].ChainID(), input)
+	if err != nil {
+		return nil, err
+	}
+	outputToken, err := registry.Token(pools[0].ChainID(), output)
+	if err != nil {
+		return nil, err
+	}
+	return NewRoute(pools, inputToken, outputToken)
+}
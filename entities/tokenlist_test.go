@@ -0,0 +1,96 @@
+package entities
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+const tokenListJSON = `{
+	"name": "Test List",
+	"timestamp": "2023-01-01T00:00:00.000Z",
+	"version": {"major": 1, "minor": 2, "patch": 0},
+	"tags": {"stablecoin": {"name": "Stablecoin", "description": "Tokens pegged to a fiat currency"}},
+	"tokens": [
+		{"chainId": 1, "address": "0xA0b86991c6218b36c1d19D4a2e9Eb0cE3606eB48", "decimals": 6, "symbol": "USDC", "name": "USD Coin", "tags": ["stablecoin"]},
+		{"chainId": 1, "address": "0x6B175474E89094C44Da98b954EedeAC495271d0F", "decimals": 18, "symbol": "DAI", "name": "Dai Stablecoin", "tags": ["stablecoin"]},
+		{"chainId": 1, "address": "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2", "decimals": 18, "symbol": "WETH", "name": "Wrapped Ether"},
+		{"chainId": 4, "address": "0xc7AD46e0b8a400Bb3C915120d284AafbA8fc4735", "decimals": 18, "symbol": "DAI", "name": "Dai Stablecoin"}
+	]
+}`
+
+func TestTokenRegistry(t *testing.T) {
+	list, err := ParseTokenList([]byte(tokenListJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, "Test List", list.Name)
+	assert.Equal(t, TokenListVersion{Major: 1, Minor: 2}, list.Version)
+	registry, err := NewTokenRegistry(list)
+	assert.NoError(t, err)
+
+	usdc, err := registry.Token(1, USDC.Address)
+	assert.NoError(t, err)
+	assert.True(t, usdc.Equal(USDC))
+	assert.Equal(t, uint(6), usdc.Decimals())
+	assert.Equal(t, "USD Coin", usdc.Name())
+	assert.Equal(t, []string{"stablecoin"}, registry.Tags(1, USDC.Address))
+	dai, err := registry.TokenBySymbol(4, "DAI")
+	assert.NoError(t, err)
+	assert.True(t, dai.Equal(DAIRinkeby))
+	assert.Equal(t, 3, len(registry.Tokens(1)))
+	assert.Equal(t, []*entities.Token{registry.Tokens(1)[0], usdc}, registry.TokensWithTag(1, "stablecoin"))
+
+	_, err = registry.Token(4, USDC.Address)
+	assert.ErrorIs(t, err, ErrTokenNotFound)
+	_, err = registry.TokenBySymbol(1, "USDT")
+	assert.ErrorIs(t, err, ErrTokenNotFound)
+
+	// tokens listed again with the same decimals and symbol take the tags of both lists
+	assert.NoError(t, registry.AddToken(entities.NewToken(1, DAI.Address, 18, "DAI", "Dai"), "defi"))
+	assert.Equal(t, []string{"stablecoin", "defi"}, registry.Tags(1, DAI.Address))
+	assert.ErrorIs(t, registry.AddToken(entities.NewToken(1, DAI.Address, 6, "DAI", "Dai")), ErrDuplicateToken)
+	assert.NoError(t, registry.AddToken(entities.NewToken(1, common.HexToAddress("0x01"), 18, "DAI", "Fake Dai")))
+	_, err = registry.TokenBySymbol(1, "DAI")
+	assert.ErrorIs(t, err, ErrAmbiguousSymbol)
+
+	// invalid lists add none of their tokens
+	invalid := &TokenList{Tokens: []TokenInfo{
+		{ChainID: 1, Address: "0x0000000000000000000000000000000000000002", Decimals: 18, Symbol: "T2"},
+		{ChainID: 1, Address: "0x0000000000000000000000000000000000000002", Decimals: 18, Symbol: "T2"},
+	}}
+	assert.ErrorIs(t, registry.AddList(invalid), ErrDuplicateToken)
+	_, err = registry.Token(1, common.HexToAddress("0x02"))
+	assert.ErrorIs(t, err, ErrTokenNotFound)
+	invalid.Tokens[1] = TokenInfo{ChainID: 1, Address: "0x03", Decimals: 18}
+	assert.ErrorIs(t, registry.AddList(invalid), ErrInvalidTokenAddress)
+	invalid.Tokens[1] = TokenInfo{ChainID: 1, Address: "0x0000000000000000000000000000000000000003", Decimals: 255}
+	assert.ErrorIs(t, registry.AddList(invalid), ErrInvalidTokenDecimals)
+	invalid.Tokens[1] = TokenInfo{ChainID: 1, Address: "0x0000000000000000000000000000000000000003", Tags: []string{"meme"}}
+	invalid.Tags = map[string]TokenListTag{"stablecoin": {}}
+	assert.ErrorIs(t, registry.AddList(invalid), ErrUnknownTokenTag)
+}
+
+func TestNewByAddress(t *testing.T) {
+	list, err := ParseTokenList([]byte(tokenListJSON))
+	assert.NoError(t, err)
+	registry, err := NewTokenRegistry(list)
+	assert.NoError(t, err)
+
+	pool, err := NewPoolByAddress(registry, 1, DAI.Address, USDC.Address, constants.Fee004, utils.EncodeSqrtRatioX96(constants.One, constants.One), big.NewInt(0), big.NewInt(0), 0, nil)
+	assert.NoError(t, err)
+	assert.True(t, pool.Token0.Equal(DAI))
+	assert.Equal(t, "USDC", pool.Token1.Symbol())
+	_, err = NewPoolByAddress(registry, 1, DAI.Address, common.HexToAddress("0x01"), constants.Fee004, utils.EncodeSqrtRatioX96(constants.One, constants.One), big.NewInt(0), big.NewInt(0), 0, nil)
+	assert.ErrorIs(t, err, ErrTokenNotFound)
+
+	route, err := NewRouteByAddress(registry, []*Pool{pool}, USDC.Address, DAI.Address)
+	assert.NoError(t, err)
+	assert.Equal(t, "DAI", route.Output.Symbol())
+	_, err = NewRouteByAddress[*Pool](registry, nil, USDC.Address, DAI.Address)
+	assert.ErrorIs(t, err, ErrRouteNoPools)
+}
@@ -34,7 +34,7 @@ var (
  * @param b The second trade to compare
  * @returns A sorted ordering for two neighboring elements in a trade array
  */
-func tradeComparator(a, b *Trade) int {
+func tradeComparator[P SwapPool[P]](a, b *TradeOf[P]) int {
 	if !a.InputAmount().Currency.Equal(b.InputAmount().Currency) {
 		panic(ErrInputCurrencyMismatch)
 	}
@@ -78,8 +78,8 @@ func tradeComparator(a, b *Trade) int {
  * Does not account for slippage, i.e., changes in price environment that can occur between
  * the time the trade is submitted and when it is executed.
  */
-type Trade struct {
-	Swaps     []*Swap            // The swaps of the trade, i.e. which routes and how much is swapped in each that make up the trade.
+type TradeOf[P SwapPool[P]] struct {
+	Swaps     []*SwapOf[P]       // The swaps of the trade, i.e. which routes and how much is swapped in each that make up the trade.
 	TradeType entities.TradeType // The type of trade, i.e. exact input or exact output
 
 	inputAmount    *entities.CurrencyAmount // The cached result of the input amount computation
@@ -88,12 +88,20 @@ type Trade struct {
 	priceImpact    *entities.Percent        // The cached result of the price impact computation
 }
 
-type Swap struct {
-	Route        *Route
+// Trade is a trade through Elastic pools
+type Trade = TradeOf[*Pool]
+
+// SwapOf is the part of a trade swapped through one of its routes
+type SwapOf[P SwapPool[P]] struct {
+	Route        *RouteOf[P]
 	InputAmount  *entities.CurrencyAmount
 	OutputAmount *entities.CurrencyAmount
+	TicksCrossed int // The number of initialized ticks crossed along the route, zero if the swap was not simulated
 }
 
+// Swap is the part of a trade swapped through one of its routes of Elastic pools
+type Swap = SwapOf[*Pool]
+
 /**
  * @deprecated Deprecated in favor of 'swaps' property. If the trade consists of multiple routes
  * this will return an error.
@@ -101,7 +109,7 @@ type Swap struct {
  * When the trade consists of just a single route, this returns the route of the trade,
  * i.e. which pools the trade goes through.
  */
-func (t *Trade) Route() (*Route, error) {
+func (t *TradeOf[P]) Route() (*RouteOf[P], error) {
 	if len(t.Swaps) != 1 {
 		return nil, ErrTradeHasMultipleRoutes
 	}
@@ -109,7 +117,7 @@ func (t *Trade) Route() (*Route, error) {
 }
 
 // InputAmount the input amount for the trade assuming no slippage.
-func (t *Trade) InputAmount() *entities.CurrencyAmount {
+func (t *TradeOf[P]) InputAmount() *entities.CurrencyAmount {
 	if t.inputAmount != nil {
 		return t.inputAmount
 	}
@@ -123,7 +131,7 @@ func (t *Trade) InputAmount() *entities.CurrencyAmount {
 }
 
 // OutputAmount the output amount for the trade assuming no slippage.
-func (t *Trade) OutputAmount() *entities.CurrencyAmount {
+func (t *TradeOf[P]) OutputAmount() *entities.CurrencyAmount {
 	if t.outputAmount != nil {
 		return t.outputAmount
 	}
@@ -137,7 +145,7 @@ func (t *Trade) OutputAmount() *entities.CurrencyAmount {
 }
 
 // ExecutionPrice the price expressed in terms of output amount/input amount.
-func (t *Trade) ExecutionPrice() *entities.Price {
+func (t *TradeOf[P]) ExecutionPrice() *entities.Price {
 	if t.executionPrice != nil {
 		return t.executionPrice
 	}
@@ -147,7 +155,7 @@ func (t *Trade) ExecutionPrice() *entities.Price {
 }
 
 // PriceImpact returns the percent difference between the route's mid price and the price impact
-func (t *Trade) PriceImpact() (*entities.Percent, error) {
+func (t *TradeOf[P]) PriceImpact() (*entities.Percent, error) {
 	if t.priceImpact != nil {
 		return t.priceImpact, nil
 	}
@@ -178,7 +186,7 @@ func (t *Trade) PriceImpact() (*entities.Percent, error) {
  * @param amountIn The amount being passed in
  * @returns The exact in trade
  */
-func ExactIn(route *Route, amountIn *entities.CurrencyAmount) (*Trade, error) {
+func ExactIn[P SwapPool[P]](route *RouteOf[P], amountIn *entities.CurrencyAmount) (*TradeOf[P], error) {
 	return FromRoute(route, amountIn, entities.ExactInput)
 }
 
@@ -190,7 +198,7 @@ func ExactIn(route *Route, amountIn *entities.CurrencyAmount) (*Trade, error) {
  * @param amountOut The amount returned by the trade
  * @returns The exact out trade
  */
-func ExactOut(route *Route, amountOut *entities.CurrencyAmount) (*Trade, error) {
+func ExactOut[P SwapPool[P]](route *RouteOf[P], amountOut *entities.CurrencyAmount) (*TradeOf[P], error) {
 	return FromRoute(route, amountOut, entities.ExactOutput)
 }
 
@@ -204,11 +212,13 @@ func ExactOut(route *Route, amountOut *entities.CurrencyAmount) (*Trade, error)
  * @param tradeType whether the trade is an exact input or exact output swap
  * @returns The route
  */
-func FromRoute(route *Route, amount *entities.CurrencyAmount, tradeType entities.TradeType) (*Trade, error) {
+func FromRoute[P SwapPool[P]](route *RouteOf[P], amount *entities.CurrencyAmount, tradeType entities.TradeType) (*TradeOf[P], error) {
 	amounts := make([]*entities.CurrencyAmount, len(route.TokenPath))
 	var (
 		inputAmount  *entities.CurrencyAmount
 		outputAmount *entities.CurrencyAmount
+		ticksCrossed int
+		crossed      int
 		err          error
 	)
 	if tradeType == entities.ExactInput {
@@ -217,12 +227,12 @@ func FromRoute(route *Route, amount *entities.CurrencyAmount, tradeType entities
 		}
 		amounts[0] = amount.Wrapped()
 		for i := 0; i < len(route.TokenPath)-1; i++ {
-			pool := route.Pools[i]
-			outputAmount, _, err = pool.GetOutputAmount(amounts[i], nil)
+			outputAmount, crossed, err = swapOutput(route.Pools[i], amounts[i])
 			if err != nil {
 				return nil, err
 			}
 			amounts[i+1] = outputAmount
+			ticksCrossed += crossed
 		}
 		inputAmount = entities.FromFractionalAmount(route.Input, amount.Numerator, amount.Denominator)
 		outputAmount = entities.FromFractionalAmount(route.Output, amounts[len(amounts)-1].Numerator, amounts[len(amounts)-1].Denominator)
@@ -232,29 +242,32 @@ func FromRoute(route *Route, amount *entities.CurrencyAmount, tradeType entities
 		}
 		amounts[len(amounts)-1] = amount.Wrapped()
 		for i := len(route.TokenPath) - 1; i > 0; i-- {
-			pool := route.Pools[i-1]
-			inputAmount, _, err = pool.GetInputAmount(amounts[i], nil)
+			inputAmount, crossed, err = swapInput(route.Pools[i-1], amounts[i])
 			if err != nil {
 				return nil, err
 			}
 			amounts[i-1] = inputAmount
+			ticksCrossed += crossed
 		}
 		inputAmount = entities.FromFractionalAmount(route.Input, amounts[0].Numerator, amounts[0].Denominator)
 		outputAmount = entities.FromFractionalAmount(route.Output, amount.Numerator, amount.Denominator)
 	}
-	swaps := []*Swap{{
+	swaps := []*SwapOf[P]{{
 		Route:        route,
 		InputAmount:  inputAmount,
-		OutputAmount: outputAmount}}
+		OutputAmount: outputAmount,
+		TicksCrossed: ticksCrossed}}
 
 	return newTrade(swaps, tradeType)
 }
 
-type WrappedRoute struct {
+type WrappedRouteOf[P SwapPool[P]] struct {
 	Amount *entities.CurrencyAmount
-	Route  *Route
+	Route  *RouteOf[P]
 }
 
+type WrappedRoute = WrappedRouteOf[*Pool]
+
 /**
  * Constructs a trade from routes by simulating swaps
  *
@@ -265,13 +278,14 @@ type WrappedRoute struct {
  * @param tradeType whether the trade is an exact input or exact output swap
  * @returns The trade
  */
-func FromRoutes(wrappedRoutes []*WrappedRoute, tradeType entities.TradeType) (*Trade, error) {
-	var swaps []*Swap
+func FromRoutes[P SwapPool[P]](wrappedRoutes []*WrappedRouteOf[P], tradeType entities.TradeType) (*TradeOf[P], error) {
+	var swaps []*SwapOf[P]
 	for _, wrappedRoute := range wrappedRoutes {
 		amounts := make([]*entities.CurrencyAmount, len(wrappedRoute.Route.TokenPath))
 		var (
 			inputAmount  *entities.CurrencyAmount
 			outputAmount *entities.CurrencyAmount
+			ticksCrossed int
 		)
 		amount := wrappedRoute.Amount
 		route := wrappedRoute.Route
@@ -281,12 +295,12 @@ func FromRoutes(wrappedRoutes []*WrappedRoute, tradeType entities.TradeType) (*T
 			}
 			amounts[0] = entities.FromFractionalAmount(route.Input.Wrapped(), amount.Numerator, amount.Denominator)
 			for i := 0; i < len(route.TokenPath)-1; i++ {
-				pool := route.Pools[i]
-				outputAmount, _, err := pool.GetOutputAmount(amounts[i], nil)
+				outputAmount, crossed, err := swapOutput(route.Pools[i], amounts[i])
 				if err != nil {
 					return nil, err
 				}
 				amounts[i+1] = outputAmount
+				ticksCrossed += crossed
 			}
 			inputAmount = entities.FromFractionalAmount(route.Input, amount.Numerator, amount.Denominator)
 			outputAmount = entities.FromFractionalAmount(route.Output, amounts[len(amounts)-1].Numerator, amounts[len(amounts)-1].Denominator)
@@ -296,20 +310,21 @@ func FromRoutes(wrappedRoutes []*WrappedRoute, tradeType entities.TradeType) (*T
 			}
 			amounts[len(amounts)-1] = entities.FromFractionalAmount(route.Output.Wrapped(), amount.Numerator, amount.Denominator)
 			for i := len(route.TokenPath) - 1; i > 0; i-- {
-				pool := route.Pools[i-1]
-				inputAmount, _, err := pool.GetInputAmount(amounts[i], nil)
+				inputAmount, crossed, err := swapInput(route.Pools[i-1], amounts[i])
 				if err != nil {
 					return nil, err
 				}
 				amounts[i-1] = inputAmount
+				ticksCrossed += crossed
 			}
 			inputAmount = entities.FromFractionalAmount(route.Input, amounts[0].Numerator, amounts[0].Denominator)
 			outputAmount = entities.FromFractionalAmount(route.Output, amount.Numerator, amount.Denominator)
 		}
-		swaps = append(swaps, &Swap{
+		swaps = append(swaps, &SwapOf[P]{
 			Route:        route,
 			InputAmount:  inputAmount,
-			OutputAmount: outputAmount})
+			OutputAmount: outputAmount,
+			TicksCrossed: ticksCrossed})
 
 	}
 	return newTrade(swaps, tradeType)
@@ -324,8 +339,8 @@ func FromRoutes(wrappedRoutes []*WrappedRoute, tradeType entities.TradeType) (*T
  * @param constructorArguments The arguments passed to the trade constructor
  * @returns The unchecked trade
  */
-func CreateUncheckedTrade(route *Route, inputAmount, outputAmount *entities.CurrencyAmount, tradeType entities.TradeType) (*Trade, error) {
-	swaps := []*Swap{{
+func CreateUncheckedTrade[P SwapPool[P]](route *RouteOf[P], inputAmount, outputAmount *entities.CurrencyAmount, tradeType entities.TradeType) (*TradeOf[P], error) {
+	swaps := []*SwapOf[P]{{
 		Route:        route,
 		InputAmount:  inputAmount,
 		OutputAmount: outputAmount}}
@@ -341,7 +356,7 @@ func CreateUncheckedTrade(route *Route, inputAmount, outputAmount *entities.Curr
  * @param constructorArguments The arguments passed to the trade constructor
  * @returns The unchecked trade
  */
-func CreateUncheckedTradeWithMultipleRoutes(routes []*Swap, tradeType entities.TradeType) (*Trade, error) {
+func CreateUncheckedTradeWithMultipleRoutes[P SwapPool[P]](routes []*SwapOf[P], tradeType entities.TradeType) (*TradeOf[P], error) {
 	return newTrade(routes, tradeType)
 }
 
@@ -350,7 +365,7 @@ func CreateUncheckedTradeWithMultipleRoutes(routes []*Swap, tradeType entities.T
  * @param routes The routes through which the trade occurs
  * @param tradeType The type of trade, exact input or exact output
  */
-func newTrade(routes []*Swap, tradeType entities.TradeType) (*Trade, error) {
+func newTrade[P SwapPool[P]](routes []*SwapOf[P], tradeType entities.TradeType) (*TradeOf[P], error) {
 	inputCurrency := routes[0].InputAmount.Currency
 	outputCurrency := routes[0].OutputAmount.Currency
 	for _, route := range routes {
@@ -370,7 +385,7 @@ func newTrade(routes []*Swap, tradeType entities.TradeType) (*Trade, error) {
 	var poolAddressSet = make(map[common.Address]bool)
 	for _, route := range routes {
 		for _, pool := range route.Route.Pools {
-			addr, err := GetAddress(pool.Token0, pool.Token1, pool.Fee, "")
+			addr, err := pool.Address()
 			if err != nil {
 				return nil, err
 			}
@@ -382,7 +397,7 @@ func newTrade(routes []*Swap, tradeType entities.TradeType) (*Trade, error) {
 		return nil, ErrDuplicatePools
 	}
 
-	return &Trade{
+	return &TradeOf[P]{
 		Swaps:     routes,
 		TradeType: tradeType,
 	}, nil
@@ -393,7 +408,7 @@ func newTrade(routes []*Swap, tradeType entities.TradeType) (*Trade, error) {
  * @param slippageTolerance The tolerance of unfavorable slippage from the execution price of this trade
  * @returns The amount out
  */
-func (t *Trade) MinimumAmountOut(slippageTolerance *entities.Percent, amountOut *entities.CurrencyAmount) (*entities.CurrencyAmount, error) {
+func (t *TradeOf[P]) MinimumAmountOut(slippageTolerance *entities.Percent, amountOut *entities.CurrencyAmount) (*entities.CurrencyAmount, error) {
 	if amountOut == nil {
 		amountOut = t.OutputAmount()
 	}
@@ -416,7 +431,7 @@ func (t *Trade) MinimumAmountOut(slippageTolerance *entities.Percent, amountOut
  * @param slippageTolerance The tolerance of unfavorable slippage from the execution price of this trade
  * @returns The amount in
  */
-func (t *Trade) MaximumAmountIn(slippageTolerance *entities.Percent, amountIn *entities.CurrencyAmount) (*entities.CurrencyAmount, error) {
+func (t *TradeOf[P]) MaximumAmountIn(slippageTolerance *entities.Percent, amountIn *entities.CurrencyAmount) (*entities.CurrencyAmount, error) {
 	if amountIn == nil {
 		amountIn = t.InputAmount()
 	}
@@ -438,7 +453,7 @@ func (t *Trade) MaximumAmountIn(slippageTolerance *entities.Percent, amountIn *e
  * @param slippageTolerance the allowed tolerated slippage
  * @returns The execution price
  */
-func (t *Trade) WorstExecutionPrice(slippageTolerance *entities.Percent) (*entities.Price, error) {
+func (t *TradeOf[P]) WorstExecutionPrice(slippageTolerance *entities.Percent) (*entities.Price, error) {
 	maxAmountIn, err := t.MaximumAmountIn(slippageTolerance, nil)
 	if err != nil {
 		return nil, err
@@ -451,8 +466,17 @@ func (t *Trade) WorstExecutionPrice(slippageTolerance *entities.Percent) (*entit
 }
 
 type BestTradeOptions struct {
-	MaxNumResults int // how many results to return
-	MaxHops       int // the maximum number of hops a trade should contain
+	MaxNumResults int       // how many results to return
+	MaxHops       int       // the maximum number of hops a trade should contain
+	GasModel      *GasModel // optional, ranks trades by their amounts net of gas cost when set
+}
+
+// bestTradeComparator returns the trade comparator to rank results with
+func bestTradeComparator[P SwapPool[P]](o *BestTradeOptions) func(a, b *TradeOf[P]) int {
+	if o.GasModel != nil {
+		return gasTradeComparator[P](o.GasModel)
+	}
+	return tradeComparator[P]
 }
 
 /**
@@ -471,6 +495,31 @@ type BestTradeOptions struct {
  * @returns The exact in trade
  */
 func BestTradeExactIn(pools []*Pool, currencyAmountIn *entities.CurrencyAmount, currencyOut entities.Currency, opts *BestTradeOptions, currentPools []*Pool, nextAmountIn *entities.CurrencyAmount, bestTrades []*Trade) ([]*Trade, error) {
+	return BestTradeExactInOf(pools, currencyAmountIn, currencyOut, opts, currentPools, nextAmountIn, bestTrades)
+}
+
+/**
+ * similar to the above method but instead targets a fixed output amount
+ * given a list of pools, and a fixed amount out, returns the top `maxNumResults` trades that go from an input token
+ * to an output token amount, making at most `maxHops` hops
+ * note this does not consider aggregation, as routes are linear. it's possible a better route exists by splitting
+ * the amount in among multiple routes.
+ * @param pools the pools to consider in finding the best trade
+ * @param currencyIn the currency to spend
+ * @param currencyAmountOut the desired currency amount out
+ * @param nextAmountOut the exact amount of currency out
+ * @param maxNumResults maximum number of results to return
+ * @param maxHops maximum number of hops a returned trade can make, e.g. 1 hop goes through a single pool
+ * @param currentPools used in recursion; the current list of pools
+ * @param bestTrades used in recursion; the current list of best trades
+ * @returns The exact out trade
+ */
+func BestTradeExactOut(pools []*Pool, currencyIn entities.Currency, currencyAmountOut *entities.CurrencyAmount, opts *BestTradeOptions, currentPools []*Pool, nextAmountOut *entities.CurrencyAmount, bestTrades []*Trade) ([]*Trade, error) {
+	return BestTradeExactOutOf(pools, currencyIn, currencyAmountOut, opts, currentPools, nextAmountOut, bestTrades)
+}
+
+// BestTradeExactInOf is BestTradeExactIn over pools of any protocol, e.g. AnyPool to route through several of them
+func BestTradeExactInOf[P SwapPool[P]](pools []P, currencyAmountIn *entities.CurrencyAmount, currencyOut entities.Currency, opts *BestTradeOptions, currentPools []P, nextAmountIn *entities.CurrencyAmount, bestTrades []*TradeOf[P]) ([]*TradeOf[P], error) {
 	if len(pools) <= 0 {
 		return nil, ErrNoPools
 	}
@@ -487,22 +536,24 @@ func BestTradeExactIn(pools []*Pool, currencyAmountIn *entities.CurrencyAmount,
 	if !(currencyAmountIn.EqualTo(nextAmountIn.Fraction) || len(currentPools) > 0) {
 		return nil, ErrInvalidRecursion
 	}
+	// the gas model is validated once, the recursion passes it on as is
+	if opts.GasModel != nil && len(currentPools) == 0 {
+		if err := opts.GasModel.validate(currencyAmountIn.Currency, currencyOut, entities.ExactInput); err != nil {
+			return nil, err
+		}
+	}
 
 	amountIn := nextAmountIn.Wrapped()
 	for i := 0; i < len(pools); i++ {
 		pool := pools[i]
 		//  pool irrelevant
-		if !pool.Token0.Equal(amountIn.Currency) && !pool.Token1.Equal(amountIn.Currency) {
+		if !pool.InvolvesToken(amountIn.Currency.Wrapped()) {
 			continue
 		}
 		amountOut, _, err := pool.GetOutputAmount(amountIn, nil)
 		if err != nil {
-			// TODO
-			// input too low
-			//  if (error.isInsufficientInputAmountError) {
-			// 	continue
-			//       }
-			return nil, err
+			// a pool that can not simulate the swap, e.g. for an input too low or a lack of liquidity, is not part of any trade
+			continue
 		}
 		// we have arrived at the output token, so this is the final trade of one of the paths
 		if amountOut.Currency.IsToken() && amountOut.Currency.Equal(tokenOut) {
@@ -512,19 +563,19 @@ func BestTradeExactIn(pools []*Pool, currencyAmountIn *entities.CurrencyAmount,
 			}
 			trade, err := FromRoute(r, currencyAmountIn, entities.ExactInput)
 			if err != nil {
-				return nil, err
+				continue
 			}
-			bestTrades, err = sortedInsert(bestTrades, trade, opts.MaxNumResults, tradeComparator)
+			bestTrades, err = sortedInsert(bestTrades, trade, opts.MaxNumResults, bestTradeComparator[P](opts))
 			if err != nil {
 				return nil, err
 			}
 		} else if opts.MaxHops > 1 && len(pools) > 1 {
-			var poolsExcludingThisPool []*Pool
+			var poolsExcludingThisPool []P
 			poolsExcludingThisPool = append(poolsExcludingThisPool, pools[:i]...)
 			poolsExcludingThisPool = append(poolsExcludingThisPool, pools[i+1:]...)
 
 			// otherwise, consider all the other paths that lead from this token as long as we have not exceeded maxHops
-			bestTrades, err = BestTradeExactIn(poolsExcludingThisPool, currencyAmountIn, currencyOut, &BestTradeOptions{MaxNumResults: opts.MaxNumResults, MaxHops: opts.MaxHops - 1}, append(currentPools, pool), amountOut, bestTrades)
+			bestTrades, err = BestTradeExactInOf(poolsExcludingThisPool, currencyAmountIn, currencyOut, &BestTradeOptions{MaxNumResults: opts.MaxNumResults, MaxHops: opts.MaxHops - 1, GasModel: opts.GasModel}, append(currentPools, pool), amountOut, bestTrades)
 			if err != nil {
 				return nil, err
 			}
@@ -533,23 +584,8 @@ func BestTradeExactIn(pools []*Pool, currencyAmountIn *entities.CurrencyAmount,
 	return bestTrades, nil
 }
 
-/**
- * similar to the above method but instead targets a fixed output amount
- * given a list of pools, and a fixed amount out, returns the top `maxNumResults` trades that go from an input token
- * to an output token amount, making at most `maxHops` hops
- * note this does not consider aggregation, as routes are linear. it's possible a better route exists by splitting
- * the amount in among multiple routes.
- * @param pools the pools to consider in finding the best trade
- * @param currencyIn the currency to spend
- * @param currencyAmountOut the desired currency amount out
- * @param nextAmountOut the exact amount of currency out
- * @param maxNumResults maximum number of results to return
- * @param maxHops maximum number of hops a returned trade can make, e.g. 1 hop goes through a single pool
- * @param currentPools used in recursion; the current list of pools
- * @param bestTrades used in recursion; the current list of best trades
- * @returns The exact out trade
- */
-func BestTradeExactOut(pools []*Pool, currencyIn entities.Currency, currencyAmountOut *entities.CurrencyAmount, opts *BestTradeOptions, currentPools []*Pool, nextAmountOut *entities.CurrencyAmount, bestTrades []*Trade) ([]*Trade, error) {
+// BestTradeExactOutOf is BestTradeExactOut over pools of any protocol, e.g. AnyPool to route through several of them
+func BestTradeExactOutOf[P SwapPool[P]](pools []P, currencyIn entities.Currency, currencyAmountOut *entities.CurrencyAmount, opts *BestTradeOptions, currentPools []P, nextAmountOut *entities.CurrencyAmount, bestTrades []*TradeOf[P]) ([]*TradeOf[P], error) {
 	if len(pools) <= 0 {
 		return nil, ErrNoPools
 	}
@@ -566,44 +602,46 @@ func BestTradeExactOut(pools []*Pool, currencyIn entities.Currency, currencyAmou
 	if !(currencyAmountOut.EqualTo(nextAmountOut.Fraction) || len(currentPools) > 0) {
 		return nil, ErrInvalidRecursion
 	}
+	// the gas model is validated once, the recursion passes it on as is
+	if opts.GasModel != nil && len(currentPools) == 0 {
+		if err := opts.GasModel.validate(currencyIn, currencyAmountOut.Currency, entities.ExactOutput); err != nil {
+			return nil, err
+		}
+	}
 
 	amountOut := nextAmountOut.Wrapped()
 	for i := 0; i < len(pools); i++ {
 		pool := pools[i]
 		// pool irrelevant
-		if !pool.Token0.Equal(amountOut.Currency) && !pool.Token1.Equal(amountOut.Currency) {
+		if !pool.InvolvesToken(amountOut.Currency.Wrapped()) {
 			continue
 		}
 		amountIn, _, err := pool.GetInputAmount(amountOut, nil)
 		if err != nil {
-			// TODO
-			// not enough liquidity in this pool
-			// if error.isInsufficientReservesError {
-			// 	continue
-			// }
-			return nil, err
+			// a pool that can not simulate the swap, e.g. for a lack of liquidity, is not part of any trade
+			continue
 		}
 		// we have arrived at the input token, so this is the final trade of one of the paths
 		if amountIn.Currency.Equal(tokenIn) {
-			r, err := NewRoute(append([]*Pool{pool}, currentPools...), currencyIn, currencyAmountOut.Currency)
+			r, err := NewRoute(append([]P{pool}, currentPools...), currencyIn, currencyAmountOut.Currency)
 			if err != nil {
 				return nil, err
 			}
 			trade, err := FromRoute(r, currencyAmountOut, entities.ExactOutput)
 			if err != nil {
-				return nil, err
+				continue
 			}
-			bestTrades, err = sortedInsert(bestTrades, trade, opts.MaxNumResults, tradeComparator)
+			bestTrades, err = sortedInsert(bestTrades, trade, opts.MaxNumResults, bestTradeComparator[P](opts))
 			if err != nil {
 				return nil, err
 			}
 		} else if opts.MaxHops > 1 && len(pools) > 1 {
-			var poolsExcludingThisPool []*Pool
+			var poolsExcludingThisPool []P
 			poolsExcludingThisPool = append(poolsExcludingThisPool, pools[:i]...)
 			poolsExcludingThisPool = append(poolsExcludingThisPool, pools[i+1:]...)
 
 			// otherwise, consider all the other paths that arrive at this token as long as we have not exceeded maxHops
-			bestTrades, err = BestTradeExactOut(poolsExcludingThisPool, currencyIn, currencyAmountOut, &BestTradeOptions{MaxNumResults: opts.MaxNumResults, MaxHops: opts.MaxHops - 1}, append([]*Pool{pool}, currentPools...), amountIn, bestTrades)
+			bestTrades, err = BestTradeExactOutOf(poolsExcludingThisPool, currencyIn, currencyAmountOut, &BestTradeOptions{MaxNumResults: opts.MaxNumResults, MaxHops: opts.MaxHops - 1, GasModel: opts.GasModel}, append([]P{pool}, currentPools...), amountIn, bestTrades)
 			if err != nil {
 				return nil, err
 			}
@@ -614,7 +652,7 @@ func BestTradeExactOut(pools []*Pool, currencyIn entities.Currency, currencyAmou
 
 // sortedInsert given an array of items sorted by `comparator`, insert an item into its sort index and constrain the size to
 // `maxSize` by removing the last item
-func sortedInsert(items []*Trade, add *Trade, maxSize int, comparator func(a, b *Trade) int) ([]*Trade, error) {
+func sortedInsert[P SwapPool[P]](items []*TradeOf[P], add *TradeOf[P], maxSize int, comparator func(a, b *TradeOf[P]) int) ([]*TradeOf[P], error) {
 	if maxSize <= 0 {
 		return nil, ErrInvalidMaxSize
 	}
@@ -7,6 +7,7 @@ replace github.com/daoleno/uniswap-sdk-core v0.1.5 => github.com/KyberNetwork/un
 require (
 	github.com/daoleno/uniswap-sdk-core v0.1.5
 	github.com/ethereum/go-ethereum v1.10.20
+	github.com/holiman/uint256 v1.2.4
 	github.com/shopspring/decimal v1.3.1
 	github.com/stretchr/testify v1.8.0
 )
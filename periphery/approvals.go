@@ -0,0 +1,211 @@
+package periphery
+
+import (
+	"bytes"
+	_ "embed"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+//go:embed contracts/interfaces/IERC20Metadata.sol/IERC20Metadata.json
+var erc20ABI []byte
+
+var (
+	ErrApprovalPoolRequired            = errors.New("the pool of the position is required to plan increaseLiquidity approvals")
+	ErrApprovalPositionManagerRequired = errors.New("the position manager is required to plan farm deposit approvals")
+)
+
+type ApprovalKind int
+
+const (
+	ApprovalERC20   ApprovalKind = iota // An ERC-20 approval of the contract the calldata is sent to
+	ApprovalPermit2                     // An ERC-20 approval of Permit2, for a token permitted in a Permit2 pre call to a spender pulling through Permit2
+	ApprovalNFT                         // An approval of the contract for every position NFT of the owner
+)
+
+// An approval missing for the calldata to execute
+type Approval struct {
+	Kind     ApprovalKind
+	Token    common.Address // The token to approve, the position manager for NFT approvals
+	Spender  common.Address
+	Amount   *big.Int // The amount spent, nil for NFT approvals
+	Calldata []byte   // The approve or setApprovalForAll call to send to Token
+	Permit   *Permit  // For ERC-20 approvals, the EIP-2612 permit that can replace the approval if the token supports it, its nonce and deadline are left to the caller
+}
+
+// The allowances the owner currently granted, as read by the caller
+type Allowances struct {
+	Owner             common.Address
+	ERC20             map[common.Address]*big.Int // The allowance of each token to the contract the calldata is sent to
+	Permit2           map[common.Address]*big.Int // The allowance of each token to Permit2
+	NFTApprovedForAll bool                        // Whether the contract the calldata is sent to may transfer every position NFT of the owner
+}
+
+// Options for planning approvals
+type ApprovalOptions struct {
+	Spender         common.Address // The contract the calldata is sent to, e.g. the router
+	WETH            common.Address // The wrapped native token, spends of which are paid with the value sent when it is enough
+	Pool            *entities.Pool // The pool of the position, required for increaseLiquidity calls
+	PositionManager common.Address // The position manager, required for farm deposits
+	Permit2Spender  bool           // Whether Spender pulls tokens through Permit2, the router and position manager do not
+}
+
+/**
+ * Returns the approvals missing for the given parameters to execute, in the order they are needed. Spends covered by a
+ * self permit in the calldata need no approval. Spends covered by a Permit2 pre call need an approval of Permit2 when the
+ * spender pulls tokens through Permit2, and an approval of the spender otherwise.
+ * @param params the parameters produced by SwapCallParameters, AddCallParameters or a farm deposit
+ * @param allowances the allowances the owner currently granted
+ * @param options the contracts involved
+ */
+func PlanApprovals(params *utils.MethodParameters, allowances *Allowances, options *ApprovalOptions) ([]*Approval, error) {
+	if isFarmDeposit(params.Calldata) {
+		if options.PositionManager == (common.Address{}) {
+			return nil, ErrApprovalPositionManagerRequired
+		}
+		if allowances.NFTApprovedForAll {
+			return nil, nil
+		}
+		calldata, err := getNonFungiblePositionManagerABI().Pack("setApprovalForAll", options.Spender, true)
+		if err != nil {
+			return nil, err
+		}
+		return []*Approval{{Kind: ApprovalNFT, Token: options.PositionManager, Spender: options.Spender, Calldata: calldata}}, nil
+	}
+
+	calls, err := DecodeMulticall(params.Calldata)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		tokens  []common.Address
+		spends  = make(map[common.Address]*big.Int)
+		permits = make(map[common.Address]*big.Int)
+		allowed = make(map[common.Address]bool)
+	)
+	spend := func(token common.Address, amount *big.Int) {
+		if _, ok := spends[token]; !ok {
+			tokens = append(tokens, token)
+			spends[token] = new(big.Int)
+		}
+		spends[token].Add(spends[token], amount)
+	}
+	for _, call := range calls {
+		switch args := call.Args.(type) {
+		case *ExactInputSingleParams:
+			spend(args.TokenIn, args.AmountIn)
+		case *ExactOutputSingleParams:
+			spend(args.TokenIn, args.AmountInMaximum)
+		case *ExactInputParams:
+			spend(call.Path.Tokens[0], args.AmountIn)
+		case *ExactOutputParams:
+			// exact output paths are encoded from the output to the input
+			spend(call.Path.Tokens[len(call.Path.Tokens)-1], args.AmountInMaximum)
+		case *MintParams:
+			spend(args.Token0, args.Amount0Desired)
+			spend(args.Token1, args.Amount1Desired)
+		case *IncreaseLiquidityParams:
+			if options.Pool == nil {
+				return nil, ErrApprovalPoolRequired
+			}
+			spend(options.Pool.Token0.Address, args.Amount0Desired)
+			spend(options.Pool.Token1.Address, args.Amount1Desired)
+		case *SelfPermitArgs:
+			permits[args.Token] = args.Value
+		case *SelfPermitAllowedArgs:
+			allowed[args.Token] = true
+		}
+	}
+
+	permit2Tokens, err := permit2PreCallTokens(params.PreCalls)
+	if err != nil {
+		return nil, err
+	}
+	value := params.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	var approvals []*Approval
+	for _, token := range tokens {
+		amount := spends[token]
+		if amount.Sign() == 0 || allowed[token] {
+			continue
+		}
+		if token == options.WETH && value.Cmp(amount) >= 0 {
+			continue
+		}
+		if permitted, ok := permits[token]; ok && permitted.Cmp(amount) >= 0 {
+			continue
+		}
+		if permit2, ok := permit2Tokens[token]; ok && options.Permit2Spender {
+			if allowanceOf(allowances.Permit2, token).Cmp(amount) >= 0 {
+				continue
+			}
+			calldata, err := GetABI(erc20ABI).Pack("approve", permit2, amount)
+			if err != nil {
+				return nil, err
+			}
+			approvals = append(approvals, &Approval{Kind: ApprovalPermit2, Token: token, Spender: permit2, Amount: amount, Calldata: calldata})
+			continue
+		}
+		if allowanceOf(allowances.ERC20, token).Cmp(amount) >= 0 {
+			continue
+		}
+		calldata, err := GetABI(erc20ABI).Pack("approve", options.Spender, amount)
+		if err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, &Approval{
+			Kind:     ApprovalERC20,
+			Token:    token,
+			Spender:  options.Spender,
+			Amount:   amount,
+			Calldata: calldata,
+			Permit:   &Permit{Owner: allowances.Owner, Spender: options.Spender, Value: amount},
+		})
+	}
+	return approvals, nil
+}
+
+// permit2PreCallTokens returns the tokens permitted by the Permit2 pre calls, mapped to the Permit2 deployment called
+func permit2PreCallTokens(preCalls []*utils.ContractCall) (map[common.Address]common.Address, error) {
+	tokens := make(map[common.Address]common.Address)
+	method, err := permit2Method(permit2PermitSingleSig)
+	if err != nil {
+		return nil, err
+	}
+	for _, call := range preCalls {
+		if len(call.Calldata) < 4 || !bytes.Equal(call.Calldata[:4], method.ID) {
+			continue
+		}
+		values, err := method.Inputs.Unpack(call.Calldata[4:])
+		if err != nil {
+			return nil, err
+		}
+		permit := abi.ConvertType(values[1], new(PermitSingle)).(*PermitSingle)
+		tokens[permit.Details.Token] = call.To
+	}
+	return tokens, nil
+}
+
+func isFarmDeposit(calldata []byte) bool {
+	if len(calldata) < 4 {
+		return false
+	}
+	return bytes.Equal(calldata[:4], GetABI(elasticLMABI).Methods["deposit"].ID) ||
+		bytes.Equal(calldata[:4], GetABI(farmingV2ABI).Methods["deposit"].ID)
+}
+
+func allowanceOf(allowances map[common.Address]*big.Int, token common.Address) *big.Int {
+	if allowance, ok := allowances[token]; ok && allowance != nil {
+		return allowance
+	}
+	return big.NewInt(0)
+}
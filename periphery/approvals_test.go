@@ -0,0 +1,100 @@
+package periphery
+
+import (
+	"math/big"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+)
+
+func TestPlanApprovals(t *testing.T) {
+	router := common.HexToAddress("0x00000000000000000000000000000000000000cc")
+	trade, err := entities.FromRoute(route_0_1, core.FromRawAmount(token0, big.NewInt(100)), core.ExactInput)
+	assert.NoError(t, err)
+	swapOptions := &SwapOptions{SlippageTolerance: slippageToleranceT, Recipient: recipientT, Deadline: deadlineT}
+	params, err := SwapCallParameters([]*entities.Trade{trade}, swapOptions)
+	assert.NoError(t, err)
+
+	approvals, err := PlanApprovals(params, &Allowances{Owner: senderT}, &ApprovalOptions{Spender: router})
+	assert.NoError(t, err)
+	assert.Len(t, approvals, 1)
+	assert.Equal(t, ApprovalERC20, approvals[0].Kind)
+	assert.Equal(t, token0.Address, approvals[0].Token)
+	assert.Equal(t, big.NewInt(100), approvals[0].Amount)
+	values, err := GetABI(erc20ABI).Methods["approve"].Inputs.Unpack(approvals[0].Calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{router, big.NewInt(100)}, values)
+	assert.Equal(t, &Permit{Owner: senderT, Spender: router, Value: big.NewInt(100)}, approvals[0].Permit)
+
+	// enough allowance
+	approvals, err = PlanApprovals(params, &Allowances{ERC20: map[common.Address]*big.Int{token0.Address: big.NewInt(100)}}, &ApprovalOptions{Spender: router})
+	assert.NoError(t, err)
+	assert.Empty(t, approvals)
+
+	// a Permit2 pre call needs Permit2 to be approved instead
+	swapOptions.InputTokenPermit2 = &Permit2Options{
+		Permit2PermitArguments: &Permit2PermitArguments{Owner: senderT, Permit: newPermitSingle(token0.Address), Signature: make([]byte, 65)},
+		Spender:                recipientT,
+	}
+	params, err = SwapCallParameters([]*entities.Trade{trade}, swapOptions)
+	assert.NoError(t, err)
+	approvals, err = PlanApprovals(params, &Allowances{}, &ApprovalOptions{Spender: router, Permit2Spender: true})
+	assert.NoError(t, err)
+	assert.Len(t, approvals, 1)
+	assert.Equal(t, ApprovalPermit2, approvals[0].Kind)
+	assert.Equal(t, Permit2Address, approvals[0].Spender)
+	approvals, err = PlanApprovals(params, &Allowances{Permit2: map[common.Address]*big.Int{token0.Address: big.NewInt(100)}}, &ApprovalOptions{Spender: router, Permit2Spender: true})
+	assert.NoError(t, err)
+	assert.Empty(t, approvals)
+
+	// the router pulls tokens with transferFrom, a Permit2 allowance does not cover its spends
+	approvals, err = PlanApprovals(params, &Allowances{Permit2: map[common.Address]*big.Int{token0.Address: big.NewInt(100)}}, &ApprovalOptions{Spender: router})
+	assert.NoError(t, err)
+	assert.Len(t, approvals, 1)
+	assert.Equal(t, ApprovalERC20, approvals[0].Kind)
+	assert.Equal(t, router, approvals[0].Spender)
+	assert.Equal(t, big.NewInt(100), approvals[0].Amount)
+
+	// a self permit covers the spend of its token
+	pos, err := entities.NewPosition(pool01T, big.NewInt(1e9), -constants.TickSpacings[feeT], constants.TickSpacings[feeT])
+	assert.NoError(t, err)
+	params, err = AddCallParameters(pos, &AddLiquidityOptions{
+		MintSpecificOptions: &MintSpecificOptions{Recipient: recipientT},
+		CommonAddLiquidityOptions: &CommonAddLiquidityOptions{
+			SlippageTolerance: slippageToleranceT,
+			Deadline:          deadlineT,
+			Token1Permit: &PermitOptions{
+				StandardPermitArguments: &StandardPermitArguments{V: 27, Amount: MaxUint128, Deadline: deadlineT},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	approvals, err = PlanApprovals(params, &Allowances{}, &ApprovalOptions{Spender: router})
+	assert.NoError(t, err)
+	assert.Len(t, approvals, 1)
+	assert.Equal(t, token0T.Address, approvals[0].Token)
+
+	// farm deposits need the position NFTs to be approved
+	farm := common.HexToAddress("0x00000000000000000000000000000000000000dd")
+	positionManager := common.HexToAddress("0x00000000000000000000000000000000000000ee")
+	params, err = ElasticLMDepositCallParameters([]*big.Int{big.NewInt(1)})
+	assert.NoError(t, err)
+	_, err = PlanApprovals(params, &Allowances{}, &ApprovalOptions{Spender: farm})
+	assert.ErrorIs(t, err, ErrApprovalPositionManagerRequired)
+	approvals, err = PlanApprovals(params, &Allowances{}, &ApprovalOptions{Spender: farm, PositionManager: positionManager})
+	assert.NoError(t, err)
+	assert.Len(t, approvals, 1)
+	assert.Equal(t, ApprovalNFT, approvals[0].Kind)
+	assert.Equal(t, positionManager, approvals[0].Token)
+	values, err = getNonFungiblePositionManagerABI().Methods["setApprovalForAll"].Inputs.Unpack(approvals[0].Calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{farm, true}, values)
+	approvals, err = PlanApprovals(params, &Allowances{NFTApprovedForAll: true}, &ApprovalOptions{Spender: farm, PositionManager: positionManager})
+	assert.NoError(t, err)
+	assert.Empty(t, approvals)
+}
@@ -0,0 +1,96 @@
+package periphery
+
+import (
+	_ "embed"
+	"errors"
+	"math/big"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+//go:embed contracts/classic/DMMRouter02.sol/DMMRouter02.json
+var classicRouterABI []byte
+
+var ErrClassicNativeInAndOut = errors.New("the input and output of a classic trade can not both be ether")
+
+// Options for producing the calldata of a swap through the KyberSwap Classic router
+type ClassicSwapOptions struct {
+	SlippageTolerance *core.Percent  // How much the execution price is allowed to move unfavorably from the trade execution price
+	Recipient         common.Address // The account that should receive the output
+	Deadline          *big.Int       // When the transaction expires, in epoch seconds
+}
+
+/**
+ * Produces the calldata to execute a trade through KyberSwap Classic pools with the classic router, which swaps along
+ * a single route. Ether in or out of the trade is wrapped or unwrapped by the router.
+ * @param trade the trade, through a single route
+ * @param options options for the call parameters
+ */
+func ClassicSwapCallParameters(trade *entities.TradeOf[*entities.ClassicPool], options *ClassicSwapOptions) (*utils.MethodParameters, error) {
+	route, err := trade.Route()
+	if err != nil {
+		return nil, err
+	}
+	etherIn := trade.InputAmount().Currency.IsNative()
+	etherOut := trade.OutputAmount().Currency.IsNative()
+	if etherIn && etherOut {
+		return nil, ErrClassicNativeInAndOut
+	}
+	amountIn, err := trade.MaximumAmountIn(options.SlippageTolerance, nil)
+	if err != nil {
+		return nil, err
+	}
+	amountOut, err := trade.MinimumAmountOut(options.SlippageTolerance, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	poolsPath := make([]common.Address, len(route.Pools))
+	for i, pool := range route.Pools {
+		poolsPath[i] = pool.PoolAddress
+	}
+	path := make([]common.Address, len(route.TokenPath))
+	for i, token := range route.TokenPath {
+		path[i] = token.Address
+	}
+
+	var (
+		method string
+		args   []interface{}
+		value  = big.NewInt(0)
+	)
+	if trade.TradeType == core.ExactInput {
+		switch {
+		case etherIn:
+			method, args, value = "swapExactETHForTokens", []interface{}{amountOut.Quotient()}, amountIn.Quotient()
+		case etherOut:
+			method, args = "swapExactTokensForETH", []interface{}{amountIn.Quotient(), amountOut.Quotient()}
+		default:
+			method, args = "swapExactTokensForTokens", []interface{}{amountIn.Quotient(), amountOut.Quotient()}
+		}
+	} else {
+		switch {
+		case etherIn:
+			// the ether not spent is refunded by the router
+			method, args, value = "swapETHForExactTokens", []interface{}{amountOut.Quotient()}, amountIn.Quotient()
+		case etherOut:
+			method, args = "swapTokensForExactETH", []interface{}{amountOut.Quotient(), amountIn.Quotient()}
+		default:
+			method, args = "swapTokensForExactTokens", []interface{}{amountOut.Quotient(), amountIn.Quotient()}
+		}
+	}
+	args = append(args, poolsPath, path, options.Recipient, options.Deadline)
+
+	calldata, err := GetABI(classicRouterABI).Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{
+		Calldata: calldata,
+		Value:    value,
+	}, nil
+}
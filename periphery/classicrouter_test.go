@@ -0,0 +1,72 @@
+package periphery
+
+import (
+	"math/big"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+)
+
+func TestClassicSwapCallParameters(t *testing.T) {
+	classicPool := func(address string, a, b *core.Token) *entities.ClassicPool {
+		pool, err := entities.NewClassicPool(common.HexToAddress(address), core.FromRawAmount(a, big.NewInt(1_000_000)), core.FromRawAmount(b, big.NewInt(1_000_000)), nil, nil, entities.ClassicBps, big.NewInt(0))
+		assert.NoError(t, err)
+		return pool
+	}
+	pool_0_1 := classicPool("0xc1", token0, token1)
+	pool_1_weth := classicPool("0xc2", token1, weth)
+	recipient := common.HexToAddress("0x0000000000000000000000000000000000000003")
+	options := &ClassicSwapOptions{SlippageTolerance: core.NewPercent(big.NewInt(1), big.NewInt(100)), Recipient: recipient, Deadline: big.NewInt(123)}
+	method := func(calldata []byte) (string, []interface{}) {
+		routerABI := GetABI(classicRouterABI)
+		m, err := routerABI.MethodById(calldata)
+		assert.NoError(t, err)
+		args, err := m.Inputs.Unpack(calldata[4:])
+		assert.NoError(t, err)
+		return m.Name, args
+	}
+
+	route, err := entities.NewRoute([]*entities.ClassicPool{pool_0_1, pool_1_weth}, token0, ether)
+	assert.NoError(t, err)
+	trade, err := entities.ExactIn(route, core.FromRawAmount(token0, big.NewInt(100)))
+	assert.NoError(t, err)
+	params, err := ClassicSwapCallParameters(trade, options)
+	assert.NoError(t, err)
+	name, args := method(params.Calldata)
+	assert.Equal(t, "swapExactTokensForETH", name)
+	assert.Equal(t, []interface{}{
+		big.NewInt(100),
+		big.NewInt(97), // 98 less 1%
+		[]common.Address{pool_0_1.PoolAddress, pool_1_weth.PoolAddress},
+		[]common.Address{token0.Address, token1.Address, weth.Address},
+		recipient,
+		big.NewInt(123),
+	}, args)
+	assert.Equal(t, 0, params.Value.Sign())
+
+	route, err = entities.NewRoute([]*entities.ClassicPool{pool_1_weth, pool_0_1}, ether, token0)
+	assert.NoError(t, err)
+	trade, err = entities.ExactOut(route, core.FromRawAmount(token0, big.NewInt(100)))
+	assert.NoError(t, err)
+	params, err = ClassicSwapCallParameters(trade, options)
+	assert.NoError(t, err)
+	name, args = method(params.Calldata)
+	assert.Equal(t, "swapETHForExactTokens", name)
+	assert.Equal(t, big.NewInt(100), args[0])
+	maxIn, _ := trade.MaximumAmountIn(options.SlippageTolerance, nil)
+	assert.Equal(t, maxIn.Quotient(), params.Value)
+
+	route, err = entities.NewRoute([]*entities.ClassicPool{pool_0_1}, token0, token1)
+	assert.NoError(t, err)
+	trade, err = entities.ExactOut(route, core.FromRawAmount(token1, big.NewInt(100)))
+	assert.NoError(t, err)
+	params, err = ClassicSwapCallParameters(trade, options)
+	assert.NoError(t, err)
+	name, args = method(params.Calldata)
+	assert.Equal(t, "swapTokensForExactTokens", name)
+	assert.Equal(t, []interface{}{big.NewInt(100), big.NewInt(102)}, args[:2])
+}
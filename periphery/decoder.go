@@ -0,0 +1,255 @@
+package periphery
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+)
+
+const (
+	addrSize = 20
+	feeSize  = 3
+)
+
+var (
+	ErrInvalidPath       = errors.New("invalid path")
+	ErrCalldataTooShort  = errors.New("calldata too short")
+	ErrUnknownMethod     = errors.New("unknown method")
+	ErrUnsupportedMethod = errors.New("unsupported method")
+)
+
+// Path is a decoded swap path, Tokens has exactly one more element than Fees
+type Path struct {
+	Tokens []common.Address
+	Fees   []constants.FeeAmount
+}
+
+/**
+ * Decodes a tightly packed path (token, fee, token, ...) as produced by EncodeRouteToPath
+ * @param path the encoded path
+ * @returns The tokens and fees of the path, in encoded order
+ */
+func DecodePath(path []byte) (*Path, error) {
+	if len(path) < addrSize || (len(path)-addrSize)%(addrSize+feeSize) != 0 {
+		return nil, ErrInvalidPath
+	}
+	var decoded Path
+	for offset := 0; ; offset += addrSize + feeSize {
+		decoded.Tokens = append(decoded.Tokens, common.BytesToAddress(path[offset:offset+addrSize]))
+		if offset+addrSize == len(path) {
+			break
+		}
+		fee := path[offset+addrSize : offset+addrSize+feeSize]
+		decoded.Fees = append(decoded.Fees, constants.FeeAmount(uint64(fee[0])<<16|uint64(fee[1])<<8|uint64(fee[2])))
+	}
+	return &decoded, nil
+}
+
+type UnwrapWETH9Args struct {
+	AmountMinimum *big.Int
+	Recipient     common.Address
+	FeeBips       *big.Int // Only set by unwrapWETH9WithFee
+	FeeRecipient  common.Address
+}
+
+type SweepTokenArgs struct {
+	Token         common.Address
+	AmountMinimum *big.Int
+	Recipient     common.Address
+	FeeBips       *big.Int // Only set by sweepTokenWithFee
+	FeeRecipient  common.Address
+}
+
+type SelfPermitArgs struct {
+	Token    common.Address
+	Value    *big.Int
+	Deadline *big.Int
+	V        uint8
+	R        [32]byte
+	S        [32]byte
+}
+
+type SelfPermitAllowedArgs struct {
+	Token  common.Address
+	Nonce  *big.Int
+	Expiry *big.Int
+	V      uint8
+	R      [32]byte
+	S      [32]byte
+}
+
+type CreateAndInitializePoolArgs struct {
+	Token0       common.Address
+	Token1       common.Address
+	Fee          *big.Int
+	SqrtPriceX96 *big.Int
+}
+
+type NFTPermitArgs struct {
+	Spender  common.Address
+	TokenId  *big.Int
+	Deadline *big.Int
+	V        uint8
+	R        [32]byte
+	S        [32]byte
+}
+
+type BurnArgs struct {
+	TokenId *big.Int
+}
+
+type SafeTransferFromArgs struct {
+	From    common.Address
+	To      common.Address
+	TokenId *big.Int
+	Data    []byte
+}
+
+// DecodedCall is a router or position manager call decoded from its calldata
+type DecodedCall struct {
+	Method string         // The solidity name of the method, e.g. exactInput
+	Args   interface{}    // The typed arguments, e.g. *ExactInputParams, nil for methods without arguments
+	Path   *Path          // The decoded path of exactInput and exactOutput calls
+	Calls  []*DecodedCall // The nested calls of a multicall
+}
+
+// decodedArgs maps a method name to a constructor of the typed arguments it decodes into
+var decodedArgs = map[string]func() interface{}{
+	"exactInputSingle":                   func() interface{} { return new(ExactInputSingleParams) },
+	"exactOutputSingle":                  func() interface{} { return new(ExactOutputSingleParams) },
+	"exactInput":                         func() interface{} { return new(ExactInputParams) },
+	"exactOutput":                        func() interface{} { return new(ExactOutputParams) },
+	"unwrapWETH9":                        func() interface{} { return new(UnwrapWETH9Args) },
+	"unwrapWETH9WithFee":                 func() interface{} { return new(UnwrapWETH9Args) },
+	"sweepToken":                         func() interface{} { return new(SweepTokenArgs) },
+	"sweepTokenWithFee":                  func() interface{} { return new(SweepTokenArgs) },
+	"refundETH":                          nil,
+	"selfPermit":                         func() interface{} { return new(SelfPermitArgs) },
+	"selfPermitIfNecessary":              func() interface{} { return new(SelfPermitArgs) },
+	"selfPermitAllowed":                  func() interface{} { return new(SelfPermitAllowedArgs) },
+	"selfPermitAllowedIfNecessary":       func() interface{} { return new(SelfPermitAllowedArgs) },
+	"createAndInitializePoolIfNecessary": func() interface{} { return new(CreateAndInitializePoolArgs) },
+	"mint":                               func() interface{} { return new(MintParams) },
+	"increaseLiquidity":                  func() interface{} { return new(IncreaseLiquidityParams) },
+	"decreaseLiquidity":                  func() interface{} { return new(DecreaseLiquidityParams) },
+	"collect":                            func() interface{} { return new(CollectParams) },
+	"burn":                               func() interface{} { return new(BurnArgs) },
+	"permit":                             func() interface{} { return new(NFTPermitArgs) },
+	"safeTransferFrom":                   func() interface{} { return new(SafeTransferFromArgs) },
+	"migrate":                            func() interface{} { return new(MigrateParams) },
+	"multicall":                          nil,
+}
+
+var (
+	decoderMethodsOnce sync.Once
+	decoderMethods     map[[4]byte]abi.Method
+)
+
+// getDecoderMethods indexes the methods of every router, position manager and migrator ABI by selector
+func getDecoderMethods() map[[4]byte]abi.Method {
+	decoderMethodsOnce.Do(func() {
+		decoderMethods = make(map[[4]byte]abi.Method)
+		for _, raw := range [][]byte{multicallABI, swapRouterABI, paymentsABI, selfpermitABI, nonFungiblePositionManagerABI, migratorABI} {
+			for _, method := range GetABI(raw).Methods {
+				var selector [4]byte
+				copy(selector[:], method.ID)
+				decoderMethods[selector] = method
+			}
+		}
+	})
+	return decoderMethods
+}
+
+/**
+ * Decodes swap router, position manager or migrator calldata into a typed call, decoding nested multicalls recursively
+ * @param calldata the calldata, starting with the 4 byte method selector
+ * @returns The decoded call
+ */
+func DecodeCalldata(calldata []byte) (*DecodedCall, error) {
+	if len(calldata) < 4 {
+		return nil, ErrCalldataTooShort
+	}
+	var selector [4]byte
+	copy(selector[:], calldata[:4])
+	method, ok := getDecoderMethods()[selector]
+	if !ok {
+		return nil, ErrUnknownMethod
+	}
+	newArgs, ok := decodedArgs[method.RawName]
+	if !ok {
+		return nil, ErrUnsupportedMethod
+	}
+
+	values, err := method.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, err
+	}
+	call := &DecodedCall{Method: method.RawName}
+
+	if method.RawName == "multicall" {
+		var datas [][]byte
+		if err := method.Inputs.Copy(&datas, values); err != nil {
+			return nil, err
+		}
+		for _, data := range datas {
+			nested, err := DecodeCalldata(data)
+			if err != nil {
+				return nil, err
+			}
+			call.Calls = append(call.Calls, nested)
+		}
+		return call, nil
+	}
+
+	if newArgs == nil {
+		return call, nil
+	}
+	call.Args = newArgs()
+	if len(method.Inputs) == 1 && method.Inputs[0].Type.T == abi.TupleTy {
+		// Copy assigns a single argument to the first field of a struct, tuples are converted field by field instead
+		call.Args = abi.ConvertType(values[0], call.Args)
+	} else if err := method.Inputs.Copy(call.Args, values); err != nil {
+		return nil, err
+	}
+
+	switch args := call.Args.(type) {
+	case *ExactInputParams:
+		call.Path, err = DecodePath(args.Path)
+	case *ExactOutputParams:
+		call.Path, err = DecodePath(args.Path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return call, nil
+}
+
+/**
+ * Decodes calldata into the flat list of calls it performs, in execution order. Calls nested in multicalls are
+ * inlined, and calldata that is not a multicall decodes to a single call.
+ * @param calldata the calldata, starting with the 4 byte method selector
+ * @returns The decoded calls
+ */
+func DecodeMulticall(calldata []byte) ([]*DecodedCall, error) {
+	call, err := DecodeCalldata(calldata)
+	if err != nil {
+		return nil, err
+	}
+	return flattenCalls(call), nil
+}
+
+func flattenCalls(call *DecodedCall) []*DecodedCall {
+	if call.Method != "multicall" {
+		return []*DecodedCall{call}
+	}
+	var calls []*DecodedCall
+	for _, nested := range call.Calls {
+		calls = append(calls, flattenCalls(nested)...)
+	}
+	return calls
+}
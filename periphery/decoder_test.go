@@ -0,0 +1,148 @@
+package periphery
+
+import (
+	"math/big"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+)
+
+func TestDecodePath(t *testing.T) {
+	path, err := EncodeRouteToPath(route_0_1_2, false)
+	assert.NoError(t, err)
+	decoded, err := DecodePath(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{token0.Address, token1.Address, token2.Address}, decoded.Tokens)
+	assert.Equal(t, []constants.FeeAmount{constants.Fee004, constants.Fee001}, decoded.Fees)
+
+	path, err = EncodeRouteToPath(route_0_1_2, true)
+	assert.NoError(t, err)
+	decoded, err = DecodePath(path)
+	assert.NoError(t, err)
+	assert.Equal(t, []common.Address{token2.Address, token1.Address, token0.Address}, decoded.Tokens)
+	assert.Equal(t, []constants.FeeAmount{constants.Fee001, constants.Fee004}, decoded.Fees)
+
+	_, err = DecodePath(path[:len(path)-1])
+	assert.ErrorIs(t, err, ErrInvalidPath)
+	_, err = DecodePath(nil)
+	assert.ErrorIs(t, err, ErrInvalidPath)
+}
+
+func TestEncodePathByAddress(t *testing.T) {
+	registry, err := entities.NewTokenRegistry()
+	assert.NoError(t, err)
+	for _, token := range []*core.Token{token0, token1, token2} {
+		assert.NoError(t, registry.AddToken(token))
+	}
+	path := &Path{
+		Tokens: []common.Address{token0.Address, token1.Address, token2.Address},
+		Fees:   []constants.FeeAmount{constants.Fee004, constants.Fee001},
+	}
+
+	// encodes as the route through the same pools
+	for _, exactOutput := range []bool{false, true} {
+		encoded, err := EncodePathByAddress(registry, 1, path, exactOutput)
+		assert.NoError(t, err)
+		expected, err := EncodeRouteToPath(route_0_1_2, exactOutput)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, encoded)
+	}
+	assert.Equal(t, token0.Address, path.Tokens[0])
+
+	_, err = EncodePathByAddress(registry, 1, &Path{Tokens: []common.Address{token0.Address, common.HexToAddress("0x05")}, Fees: []constants.FeeAmount{constants.Fee004}}, false)
+	assert.ErrorIs(t, err, entities.ErrTokenNotFound)
+	_, err = EncodePathByAddress(registry, 1, &Path{Tokens: []common.Address{token0.Address}}, false)
+	assert.ErrorIs(t, err, ErrInvalidPath)
+}
+
+func TestDecodeSwapCalldata(t *testing.T) {
+	pool_0_1 := makePool(token0, token1)
+	pool_1_weth := makePool(token1, weth)
+	route, err := entities.NewRoute([]*entities.Pool{pool_0_1, pool_1_weth}, token0, ether)
+	assert.NoError(t, err)
+	trade, err := entities.FromRoute(route, core.FromRawAmount(token0, big.NewInt(100)), core.ExactInput)
+	assert.NoError(t, err)
+
+	params, err := SwapCallParameters([]*entities.Trade{trade}, &SwapOptions{
+		SlippageTolerance: core.NewPercent(big.NewInt(1), big.NewInt(100)),
+		Recipient:         common.HexToAddress("0x0000000000000000000000000000000000000003"),
+		Deadline:          big.NewInt(123),
+	})
+	assert.NoError(t, err)
+
+	call, err := DecodeCalldata(params.Calldata)
+	assert.NoError(t, err)
+	assert.Equal(t, "multicall", call.Method)
+	assert.Equal(t, 2, len(call.Calls))
+
+	calls, err := DecodeMulticall(params.Calldata)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(calls))
+
+	assert.Equal(t, "exactInput", calls[0].Method)
+	swap := calls[0].Args.(*ExactInputParams)
+	assert.Equal(t, big.NewInt(100), swap.AmountIn)
+	assert.Equal(t, big.NewInt(123), swap.Deadline)
+	assert.Equal(t, common.Address{}, swap.Recipient)
+	assert.Equal(t, []common.Address{token0.Address, token1.Address, weth.Address}, calls[0].Path.Tokens)
+	assert.Equal(t, []constants.FeeAmount{feeAmount, feeAmount}, calls[0].Path.Fees)
+
+	assert.Equal(t, "unwrapWETH9", calls[1].Method)
+	unwrap := calls[1].Args.(*UnwrapWETH9Args)
+	assert.Equal(t, swap.AmountOutMinimum, unwrap.AmountMinimum)
+	assert.Equal(t, common.HexToAddress("0x0000000000000000000000000000000000000003"), unwrap.Recipient)
+}
+
+func TestDecodePositionManagerCalldata(t *testing.T) {
+	params, err := CreateCallParameters(pool01T)
+	assert.NoError(t, err)
+	call, err := DecodeCalldata(params.Calldata)
+	assert.NoError(t, err)
+	assert.Equal(t, "createAndInitializePoolIfNecessary", call.Method)
+	assert.Equal(t, &CreateAndInitializePoolArgs{
+		Token0:       token0T.Address,
+		Token1:       token1T.Address,
+		Fee:          big.NewInt(int64(feeT)),
+		SqrtPriceX96: pool01T.SqrtP,
+	}, call.Args)
+
+	params, err = CollectCallParameters(&CollectOptions{
+		TokenID:               tokenIDT,
+		ExpectedCurrencyOwed0: core.FromRawAmount(token0T, big.NewInt(1)),
+		ExpectedCurrencyOwed1: core.FromRawAmount(core.EtherOnChain(1), big.NewInt(2)),
+		ExpectedTokenOwed0:    token0T,
+		ExpectedTokenOwed1:    core.EtherOnChain(1),
+		Recipient:             recipientT,
+	})
+	assert.NoError(t, err)
+	calls, err := DecodeMulticall(params.Calldata)
+	assert.NoError(t, err)
+	var methods []string
+	for _, call := range calls {
+		methods = append(methods, call.Method)
+	}
+	assert.Equal(t, []string{"collect", "unwrapWETH9", "sweepToken"}, methods)
+	assert.Equal(t, &CollectParams{
+		TokenId:    tokenIDT,
+		Recipient:  common.Address{},
+		Amount0Max: MaxUint128,
+		Amount1Max: MaxUint128,
+	}, calls[0].Args)
+	assert.Equal(t, &SweepTokenArgs{
+		Token:         token0T.Address,
+		AmountMinimum: big.NewInt(1),
+		Recipient:     recipientT,
+	}, calls[2].Args)
+
+	_, err = DecodeCalldata([]byte{0x12, 0x34})
+	assert.ErrorIs(t, err, ErrCalldataTooShort)
+	_, err = DecodeCalldata([]byte{0xde, 0xad, 0xbe, 0xef})
+	assert.ErrorIs(t, err, ErrUnknownMethod)
+	_, err = DecodeCalldata(common.FromHex("0x70a08231"))
+	assert.ErrorIs(t, err, ErrUnsupportedMethod)
+}
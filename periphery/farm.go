@@ -0,0 +1,193 @@
+package periphery
+
+import (
+	_ "embed"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+//go:embed contracts/elastic/farm/KyberSwapElasticLM.sol/KyberSwapElasticLM.json
+var elasticLMABI []byte
+
+//go:embed contracts/elastic/farm/KyberSwapFarmingV2.sol/KyberSwapFarmingV2.json
+var farmingV2ABI []byte
+
+var (
+	ErrNoFarmNFTs         = errors.New("no nft ids")
+	ErrFarmLengthMismatch = errors.New("nft ids and liquidities have different lengths")
+	ErrNoFarmPhases       = errors.New("no phase ids to harvest")
+	ErrNoFarmOperations   = errors.New("no farm operations")
+)
+
+// struct HarvestData { uint256[] pIds; }, decoded by harvestMultiplePools from each element of datas
+var harvestDataArguments = func() abi.Arguments {
+	tupleTy, _ := abi.NewType("tuple", "HarvestData", []abi.ArgumentMarshaling{
+		{Name: "pIds", Type: "uint256[]"},
+	})
+	return abi.Arguments{{Name: "data", Type: tupleTy}}
+}()
+
+// The liquidity of staked NFTs joining or exiting a phase of an ElasticLM farm
+type FarmLiquidity struct {
+	PID         *big.Int   // The id of the farming phase
+	NftIDs      []*big.Int // The staked position NFTs
+	Liquidities []*big.Int // The liquidity of each NFT to join or exit with, at most the position's liquidity
+}
+
+// The phases of an ElasticLM farm to harvest the rewards of a staked NFT from
+type FarmHarvest struct {
+	NftID *big.Int
+	PIDs  []*big.Int
+}
+
+// ElasticLMDepositCallParameters produces the calldata to stake position NFTs into an ElasticLM farm, the NFTs must be
+// approved to the farm beforehand
+func ElasticLMDepositCallParameters(nftIDs []*big.Int) (*utils.MethodParameters, error) {
+	return farmNFTsCallParameters(elasticLMABI, "deposit", nftIDs)
+}
+
+// ElasticLMWithdrawCallParameters produces the calldata to unstake position NFTs that exited every phase
+func ElasticLMWithdrawCallParameters(nftIDs []*big.Int) (*utils.MethodParameters, error) {
+	return farmNFTsCallParameters(elasticLMABI, "withdraw", nftIDs)
+}
+
+// ElasticLMEmergencyWithdrawCallParameters produces the calldata to unstake position NFTs, forfeiting their pending rewards
+func ElasticLMEmergencyWithdrawCallParameters(nftIDs []*big.Int) (*utils.MethodParameters, error) {
+	return farmNFTsCallParameters(elasticLMABI, "emergencyWithdraw", nftIDs)
+}
+
+/**
+ * Produces the calldata to join phases of an ElasticLM farm with staked NFTs
+ * @param joins the liquidity to add to each phase, the farm has no multicall so each phase is joined in its own call
+ */
+func ElasticLMJoinCallParameters(joins []*FarmLiquidity) ([]*utils.MethodParameters, error) {
+	return farmLiquidityCallParameters("join", joins)
+}
+
+/**
+ * Produces the calldata to exit phases of an ElasticLM farm, the rewards earned so far are harvested
+ * @param exits the liquidity to remove from each phase, the farm has no multicall so each phase is exited in its own call
+ */
+func ElasticLMExitCallParameters(exits []*FarmLiquidity) ([]*utils.MethodParameters, error) {
+	return farmLiquidityCallParameters("exit", exits)
+}
+
+/**
+ * Produces the calldata to harvest the rewards of staked NFTs
+ * @param harvests the phases to harvest for each NFT
+ */
+func ElasticLMHarvestCallParameters(harvests []*FarmHarvest) (*utils.MethodParameters, error) {
+	if len(harvests) == 0 {
+		return nil, ErrNoFarmNFTs
+	}
+	var (
+		nftIDs []*big.Int
+		datas  [][]byte
+	)
+	for _, harvest := range harvests {
+		if len(harvest.PIDs) == 0 {
+			return nil, ErrNoFarmPhases
+		}
+		data, err := harvestDataArguments.Pack(struct{ PIds []*big.Int }{harvest.PIDs})
+		if err != nil {
+			return nil, err
+		}
+		nftIDs = append(nftIDs, harvest.NftID)
+		datas = append(datas, data)
+	}
+	calldata, err := GetABI(elasticLMABI).Pack("harvestMultiplePools", nftIDs, datas)
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{
+		Calldata: calldata,
+		Value:    big.NewInt(0),
+	}, nil
+}
+
+/**
+ * Produces the calldata to stake position NFTs into a range of a KyberSwap farming v2 farm
+ * @param fID the id of the farm
+ * @param rangeID the id of the tick range the positions are eligible for
+ * @param nftIDs the position NFTs, approved to the farm beforehand
+ * @param receiver the account the staked NFTs are credited to
+ */
+func FarmV2DepositCallParameters(fID, rangeID *big.Int, nftIDs []*big.Int, receiver common.Address) (*utils.MethodParameters, error) {
+	if len(nftIDs) == 0 {
+		return nil, ErrNoFarmNFTs
+	}
+	return farmCallParameters(farmingV2ABI, "deposit", fID, rangeID, nftIDs, receiver)
+}
+
+// FarmV2AddLiquidityCallParameters produces the calldata to update the farming liquidity of staked NFTs after liquidity was added to the positions
+func FarmV2AddLiquidityCallParameters(fID, rangeID *big.Int, nftIDs []*big.Int) (*utils.MethodParameters, error) {
+	if len(nftIDs) == 0 {
+		return nil, ErrNoFarmNFTs
+	}
+	return farmCallParameters(farmingV2ABI, "addLiquidity", fID, rangeID, nftIDs)
+}
+
+// FarmV2ClaimRewardCallParameters produces the calldata to claim the rewards of staked NFTs in the current phase of a farm
+func FarmV2ClaimRewardCallParameters(fID *big.Int, nftIDs []*big.Int) (*utils.MethodParameters, error) {
+	if len(nftIDs) == 0 {
+		return nil, ErrNoFarmNFTs
+	}
+	return farmCallParameters(farmingV2ABI, "claimReward", fID, nftIDs)
+}
+
+// FarmV2WithdrawCallParameters produces the calldata to claim the rewards of staked NFTs and unstake them
+func FarmV2WithdrawCallParameters(fID *big.Int, nftIDs []*big.Int) (*utils.MethodParameters, error) {
+	if len(nftIDs) == 0 {
+		return nil, ErrNoFarmNFTs
+	}
+	return farmCallParameters(farmingV2ABI, "withdraw", fID, nftIDs)
+}
+
+// FarmV2EmergencyWithdrawCallParameters produces the calldata to unstake NFTs, forfeiting their pending rewards
+func FarmV2EmergencyWithdrawCallParameters(nftIDs []*big.Int) (*utils.MethodParameters, error) {
+	return farmNFTsCallParameters(farmingV2ABI, "emergencyWithdraw", nftIDs)
+}
+
+func farmNFTsCallParameters(farmABI []byte, method string, nftIDs []*big.Int) (*utils.MethodParameters, error) {
+	if len(nftIDs) == 0 {
+		return nil, ErrNoFarmNFTs
+	}
+	return farmCallParameters(farmABI, method, nftIDs)
+}
+
+func farmLiquidityCallParameters(method string, ops []*FarmLiquidity) ([]*utils.MethodParameters, error) {
+	if len(ops) == 0 {
+		return nil, ErrNoFarmOperations
+	}
+	var calls []*utils.MethodParameters
+	for _, op := range ops {
+		if len(op.NftIDs) == 0 {
+			return nil, ErrNoFarmNFTs
+		}
+		if len(op.NftIDs) != len(op.Liquidities) {
+			return nil, ErrFarmLengthMismatch
+		}
+		call, err := farmCallParameters(elasticLMABI, method, op.PID, op.NftIDs, op.Liquidities)
+		if err != nil {
+			return nil, err
+		}
+		calls = append(calls, call)
+	}
+	return calls, nil
+}
+
+func farmCallParameters(farmABI []byte, method string, args ...interface{}) (*utils.MethodParameters, error) {
+	calldata, err := GetABI(farmABI).Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{
+		Calldata: calldata,
+		Value:    big.NewInt(0),
+	}, nil
+}
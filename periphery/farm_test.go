@@ -0,0 +1,84 @@
+package periphery
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestElasticLMCallParameters(t *testing.T) {
+	nftIDs := []*big.Int{big.NewInt(1), big.NewInt(2)}
+	params, err := ElasticLMDepositCallParameters(nftIDs)
+	assert.NoError(t, err)
+	values, err := GetABI(elasticLMABI).Methods["deposit"].Inputs.Unpack(params.Calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{nftIDs}, values)
+	_, err = ElasticLMWithdrawCallParameters(nil)
+	assert.ErrorIs(t, err, ErrNoFarmNFTs)
+
+	join := &FarmLiquidity{PID: big.NewInt(3), NftIDs: nftIDs, Liquidities: []*big.Int{big.NewInt(100), big.NewInt(200)}}
+	calls, err := ElasticLMJoinCallParameters([]*FarmLiquidity{join})
+	assert.NoError(t, err)
+	assert.Len(t, calls, 1)
+	method := GetABI(elasticLMABI).Methods["join"]
+	assert.Equal(t, method.ID, calls[0].Calldata[:4])
+	values, err = method.Inputs.Unpack(calls[0].Calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(3), nftIDs, join.Liquidities}, values)
+
+	// each phase is exited in its own call to the farm
+	exit := &FarmLiquidity{PID: big.NewInt(4), NftIDs: nftIDs[:1], Liquidities: []*big.Int{big.NewInt(50)}}
+	calls, err = ElasticLMExitCallParameters([]*FarmLiquidity{join, exit})
+	assert.NoError(t, err)
+	assert.Len(t, calls, 2)
+	farmABI := GetABI(elasticLMABI)
+	for i, op := range []*FarmLiquidity{join, exit} {
+		method, err := farmABI.MethodById(calls[i].Calldata[:4])
+		assert.NoError(t, err)
+		assert.Equal(t, "exit", method.Name)
+		values, err = method.Inputs.Unpack(calls[i].Calldata[4:])
+		assert.NoError(t, err)
+		assert.Equal(t, []interface{}{op.PID, op.NftIDs, op.Liquidities}, values)
+		assert.Equal(t, big.NewInt(0), calls[i].Value)
+	}
+
+	_, err = ElasticLMJoinCallParameters([]*FarmLiquidity{{PID: big.NewInt(3), NftIDs: nftIDs, Liquidities: nftIDs[:1]}})
+	assert.ErrorIs(t, err, ErrFarmLengthMismatch)
+	_, err = ElasticLMExitCallParameters(nil)
+	assert.ErrorIs(t, err, ErrNoFarmOperations)
+
+	params, err = ElasticLMHarvestCallParameters([]*FarmHarvest{
+		{NftID: big.NewInt(1), PIDs: []*big.Int{big.NewInt(3), big.NewInt(4)}},
+		{NftID: big.NewInt(2), PIDs: []*big.Int{big.NewInt(3)}},
+	})
+	assert.NoError(t, err)
+	values, err = GetABI(elasticLMABI).Methods["harvestMultiplePools"].Inputs.Unpack(params.Calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, nftIDs, values[0])
+	datas := values[1].([][]byte)
+	data, err := harvestDataArguments.Unpack(datas[0])
+	assert.NoError(t, err)
+	assert.Equal(t, struct {
+		PIds []*big.Int `json:"pIds"`
+	}{[]*big.Int{big.NewInt(3), big.NewInt(4)}}, data[0])
+
+	_, err = ElasticLMHarvestCallParameters([]*FarmHarvest{{NftID: big.NewInt(1)}})
+	assert.ErrorIs(t, err, ErrNoFarmPhases)
+}
+
+func TestFarmV2CallParameters(t *testing.T) {
+	nftIDs := []*big.Int{big.NewInt(1)}
+	params, err := FarmV2DepositCallParameters(big.NewInt(2), big.NewInt(5), nftIDs, recipientT)
+	assert.NoError(t, err)
+	values, err := GetABI(farmingV2ABI).Methods["deposit"].Inputs.Unpack(params.Calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(2), big.NewInt(5), nftIDs, recipientT}, values)
+
+	params, err = FarmV2WithdrawCallParameters(big.NewInt(2), nftIDs)
+	assert.NoError(t, err)
+	assert.Equal(t, GetABI(farmingV2ABI).Methods["withdraw"].ID, params.Calldata[:4])
+
+	_, err = FarmV2ClaimRewardCallParameters(big.NewInt(2), nil)
+	assert.ErrorIs(t, err, ErrNoFarmNFTs)
+}
@@ -0,0 +1,189 @@
+package periphery
+
+import (
+	_ "embed"
+	"errors"
+	"math/big"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+//go:embed contracts/interfaces/IV3Migrator.sol/IV3Migrator.json
+var migratorABI []byte
+
+var (
+	ErrZeroTotalSupply       = errors.New("zero total supply")
+	ErrLiquidityAboveSupply  = errors.New("liquidity to migrate exceeds the total supply")
+	ErrInsufficientMigration = errors.New("burned amounts are not enough to mint the position")
+)
+
+// The liquidity of a Uniswap V2 or KyberSwap Classic pair to migrate
+type MigrationSource struct {
+	Pair        *core.Token // The pair, whose LP token is burned
+	Liquidity   *big.Int    // The amount of LP tokens to burn
+	TotalSupply *big.Int    // The total supply of LP tokens of the pair
+	Reserve0    *big.Int    // The reserve of the pool's token0 held by the pair, for Classic pairs the real reserve, not the virtual one
+	Reserve1    *big.Int    // The reserve of the pool's token1 held by the pair
+}
+
+/**
+ * Returns the amounts the pair sends back when the source liquidity is burned
+ * @returns The amounts of token0 and token1, rounded down like the pair contracts do
+ */
+func (s *MigrationSource) BurnAmounts() (amount0, amount1 *big.Int, err error) {
+	if s.TotalSupply.Sign() <= 0 {
+		return nil, nil, ErrZeroTotalSupply
+	}
+	if s.Liquidity.Sign() <= 0 {
+		return nil, nil, ErrZeroLiquidity
+	}
+	if s.Liquidity.Cmp(s.TotalSupply) > 0 {
+		return nil, nil, ErrLiquidityAboveSupply
+	}
+	amount0 = new(big.Int).Div(new(big.Int).Mul(s.Liquidity, s.Reserve0), s.TotalSupply)
+	amount1 = new(big.Int).Div(new(big.Int).Mul(s.Liquidity, s.Reserve1), s.TotalSupply)
+	return amount0, amount1, nil
+}
+
+// Options for producing the calldata to migrate liquidity
+type MigrateOptions struct {
+	SlippageTolerance *core.Percent  // How much the pool price is allowed to move
+	Recipient         common.Address // The account that should receive the minted NFT
+	Deadline          *big.Int       // When the transaction expires, in epoch seconds
+	RefundAsETH       bool           // Whether the part of the burned amounts that is not migrated is refunded as ETH instead of WETH
+	CreatePool        bool           // Creates pool if not initialized before migrating
+	PairPermit        *PermitOptions // The optional permit parameters for the migrator to spend the pair's LP tokens
+}
+
+type MigrateParams struct {
+	Pair                common.Address
+	LiquidityToMigrate  *big.Int
+	PercentageToMigrate uint8
+	Token0              common.Address
+	Token1              common.Address
+	Fee                 *big.Int
+	TickLower           *big.Int
+	TickUpper           *big.Int
+	Amount0Min          *big.Int
+	Amount1Min          *big.Int
+	Recipient           common.Address
+	Deadline            *big.Int
+	RefundAsETH         bool
+}
+
+/**
+ * Computes the smallest percentage of the burned amounts that is enough to mint the position. The rest of the burned
+ * amounts is refunded by the migrator.
+ * @param source the liquidity to migrate
+ * @param position the position to mint
+ * @returns The percentage, between 1 and 100
+ */
+func MigrationPercentage(source *MigrationSource, position *entities.Position) (uint8, error) {
+	burn0, burn1, err := source.BurnAmounts()
+	if err != nil {
+		return 0, err
+	}
+	mint0, mint1, err := position.MintAmounts()
+	if err != nil {
+		return 0, err
+	}
+
+	percentage := big.NewInt(1)
+	for _, amounts := range [][2]*big.Int{{mint0, burn0}, {mint1, burn1}} {
+		mint, burn := amounts[0], amounts[1]
+		if mint.Sign() == 0 {
+			continue
+		}
+		if burn.Sign() == 0 {
+			return 0, ErrInsufficientMigration
+		}
+		// the migrator mints with burn * percentage / 100, rounded down, which must cover the mint amount
+		needed, remainder := new(big.Int).QuoRem(new(big.Int).Mul(mint, big.NewInt(100)), burn, new(big.Int))
+		if remainder.Sign() > 0 {
+			needed.Add(needed, constants.One)
+		}
+		if needed.Cmp(percentage) > 0 {
+			percentage = needed
+		}
+	}
+	if percentage.Cmp(big.NewInt(100)) > 0 {
+		return 0, ErrInsufficientMigration
+	}
+	return uint8(percentage.Uint64()), nil
+}
+
+/**
+ * Produces the calldata to migrate pair liquidity into an Elastic position through the migrator
+ * @param source the liquidity to migrate, its reserves must be ordered like the tokens of the position's pool
+ * @param position the position to mint, the migrated amounts must be enough to mint its liquidity
+ * @param opts options for the migration
+ */
+func MigrateCallParameters(source *MigrationSource, position *entities.Position, opts *MigrateOptions) (*utils.MethodParameters, error) {
+	if position.Liquidity.Sign() <= 0 {
+		return nil, ErrZeroLiquidity
+	}
+	percentage, err := MigrationPercentage(source, position)
+	if err != nil {
+		return nil, err
+	}
+
+	// adjust for slippage
+	amount0Min, amount1Min, err := position.MintAmountsWithSlippage(opts.SlippageTolerance)
+	if err != nil {
+		return nil, err
+	}
+
+	var calldatas [][]byte
+
+	// create pool if needed
+	if opts.CreatePool {
+		calldata, err := encodeCreate(position.Pool)
+		if err != nil {
+			return nil, err
+		}
+		calldatas = append(calldatas, calldata)
+	}
+
+	// permit if necessary
+	if opts.PairPermit != nil {
+		calldata, err := EncodePermit(source.Pair, opts.PairPermit)
+		if err != nil {
+			return nil, err
+		}
+		calldatas = append(calldatas, calldata)
+	}
+
+	calldata, err := GetABI(migratorABI).Pack("migrate", &MigrateParams{
+		Pair:                source.Pair.Address,
+		LiquidityToMigrate:  source.Liquidity,
+		PercentageToMigrate: percentage,
+		Token0:              position.Pool.Token0.Address,
+		Token1:              position.Pool.Token1.Address,
+		Fee:                 big.NewInt(int64(position.Pool.Fee)),
+		TickLower:           big.NewInt(int64(position.TickLower)),
+		TickUpper:           big.NewInt(int64(position.TickUpper)),
+		Amount0Min:          amount0Min,
+		Amount1Min:          amount1Min,
+		Recipient:           opts.Recipient,
+		Deadline:            opts.Deadline,
+		RefundAsETH:         opts.RefundAsETH,
+	})
+	if err != nil {
+		return nil, err
+	}
+	calldatas = append(calldatas, calldata)
+
+	datas, err := EncodeMulticall(calldatas)
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{
+		Calldata: datas,
+		Value:    constants.Zero,
+	}, nil
+}
@@ -0,0 +1,102 @@
+package periphery
+
+import (
+	"math/big"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+)
+
+func TestMigrateCallParameters(t *testing.T) {
+	pair := core.NewToken(1, common.HexToAddress("0x0000000000000000000000000000000000000009"), 18, "UNI-V2", "Uniswap V2")
+	source := &MigrationSource{
+		Pair:        pair,
+		Liquidity:   big.NewInt(50),
+		TotalSupply: big.NewInt(100),
+		Reserve0:    big.NewInt(4e18),
+		Reserve1:    big.NewInt(4001e15),
+	}
+	burn0, burn1, err := source.BurnAmounts()
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(2e18), burn0)
+	assert.Equal(t, big.NewInt(20005e14), burn1)
+
+	spacing := constants.TickSpacings[feeAmount]
+	position, err := entities.FromAmounts(makePool(token0, token1), -100*spacing, 100*spacing, big.NewInt(1e18), big.NewInt(1e18), false)
+	assert.NoError(t, err)
+	mint0, mint1, err := position.MintAmounts()
+	assert.NoError(t, err)
+
+	percentage, err := MigrationPercentage(source, position)
+	assert.NoError(t, err)
+	// the smallest percentage of the burned amounts covering the mint amounts
+	for _, amounts := range [][3]*big.Int{{burn0, mint0}, {burn1, mint1}} {
+		burn, mint := amounts[0], amounts[1]
+		assert.True(t, new(big.Int).Div(new(big.Int).Mul(burn, big.NewInt(int64(percentage))), big.NewInt(100)).Cmp(mint) >= 0)
+	}
+	assert.True(t,
+		new(big.Int).Div(new(big.Int).Mul(burn0, big.NewInt(int64(percentage-1))), big.NewInt(100)).Cmp(mint0) < 0 ||
+			new(big.Int).Div(new(big.Int).Mul(burn1, big.NewInt(int64(percentage-1))), big.NewInt(100)).Cmp(mint1) < 0)
+
+	params, err := MigrateCallParameters(source, position, &MigrateOptions{
+		SlippageTolerance: slippageToleranceT,
+		Recipient:         recipientT,
+		Deadline:          deadlineT,
+		RefundAsETH:       true,
+		CreatePool:        true,
+		PairPermit: &PermitOptions{StandardPermitArguments: &StandardPermitArguments{
+			V:        27,
+			Amount:   big.NewInt(50),
+			Deadline: deadlineT,
+		}},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, constants.Zero, params.Value)
+
+	calls, err := DecodeMulticall(params.Calldata)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, len(calls))
+	assert.Equal(t, "createAndInitializePoolIfNecessary", calls[0].Method)
+	assert.Equal(t, "selfPermit", calls[1].Method)
+	assert.Equal(t, pair.Address, calls[1].Args.(*SelfPermitArgs).Token)
+	assert.Equal(t, "migrate", calls[2].Method)
+
+	amount0Min, amount1Min, err := position.MintAmountsWithSlippage(slippageToleranceT)
+	assert.NoError(t, err)
+	assert.Equal(t, &MigrateParams{
+		Pair:                pair.Address,
+		LiquidityToMigrate:  big.NewInt(50),
+		PercentageToMigrate: percentage,
+		Token0:              token0.Address,
+		Token1:              token1.Address,
+		Fee:                 big.NewInt(int64(feeAmount)),
+		TickLower:           big.NewInt(int64(-100 * spacing)),
+		TickUpper:           big.NewInt(int64(100 * spacing)),
+		Amount0Min:          amount0Min,
+		Amount1Min:          amount1Min,
+		Recipient:           recipientT,
+		Deadline:            deadlineT,
+		RefundAsETH:         true,
+	}, calls[2].Args)
+
+	// without options the migrate call is sent on its own
+	params, err = MigrateCallParameters(source, position, &MigrateOptions{SlippageTolerance: slippageToleranceT, Recipient: recipientT, Deadline: deadlineT})
+	assert.NoError(t, err)
+	call, err := DecodeCalldata(params.Calldata)
+	assert.NoError(t, err)
+	assert.Equal(t, "migrate", call.Method)
+
+	// not enough liquidity burned
+	source.Liquidity = big.NewInt(20)
+	_, err = MigrateCallParameters(source, position, &MigrateOptions{SlippageTolerance: slippageToleranceT, Recipient: recipientT, Deadline: deadlineT})
+	assert.ErrorIs(t, err, ErrInsufficientMigration)
+
+	source.Liquidity = big.NewInt(101)
+	_, _, err = source.BurnAmounts()
+	assert.ErrorIs(t, err, ErrLiquidityAboveSupply)
+}
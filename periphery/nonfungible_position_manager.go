@@ -46,11 +46,13 @@ type IncreaseSpecificOptions struct {
 
 // Options for producing the calldata to add liquidity
 type CommonAddLiquidityOptions struct {
-	SlippageTolerance *core.Percent  // How much the pool price is allowed to move
-	Deadline          *big.Int       // When the transaction expires, in epoch seconds
-	UseNative         *core.Ether    // Whether to spend ether. If true, one of the pool tokens must be WETH, by default false
-	Token0Permit      *PermitOptions // The optional permit parameters for spending token0
-	Token1Permit      *PermitOptions // The optional permit parameters for spending token1
+	SlippageTolerance *core.Percent   // How much the pool price is allowed to move
+	Deadline          *big.Int        // When the transaction expires, in epoch seconds
+	UseNative         *core.Ether     // Whether to spend ether. If true, one of the pool tokens must be WETH, by default false
+	Token0Permit      *PermitOptions  // The optional permit parameters for spending token0
+	Token1Permit      *PermitOptions  // The optional permit parameters for spending token1
+	Token0Permit2     *Permit2Options // The optional Permit2 permit for spending token0, sent to Permit2 before the operation
+	Token1Permit2     *Permit2Options // The optional Permit2 permit for spending token1, sent to Permit2 before the operation
 }
 
 type MintOptions struct {
@@ -202,6 +204,22 @@ func AddCallParameters(position *entities.Position, opts *AddLiquidityOptions) (
 		calldatas = append(calldatas, calldata)
 	}
 
+	var preCalls []*utils.ContractCall
+	for i, permit2 := range []*Permit2Options{opts.Token0Permit2, opts.Token1Permit2} {
+		if permit2 == nil {
+			continue
+		}
+		token := position.Pool.Token0
+		if i == 1 {
+			token = position.Pool.Token1
+		}
+		preCall, err := Permit2PreCall(token.Address, permit2.Spender, permit2.Permit2PermitArguments)
+		if err != nil {
+			return nil, err
+		}
+		preCalls = append(preCalls, preCall)
+	}
+
 	abi := getNonFungiblePositionManagerABI()
 
 	// mint
@@ -268,6 +286,7 @@ func AddCallParameters(position *entities.Position, opts *AddLiquidityOptions) (
 	return &utils.MethodParameters{
 		Calldata: datas,
 		Value:    value,
+		PreCalls: preCalls,
 	}, nil
 }
 
@@ -371,7 +390,7 @@ func RemoveCallParameters(position *entities.Position, opts *RemoveLiquidityOpti
 
 	abi := getNonFungiblePositionManagerABI()
 	if opts.Permit != nil {
-		calldata, err := abi.Pack("permit", opts.Permit.Spender, opts.TokenID, opts.Permit.Deadline, opts.Permit.V, opts.Permit.R, opts.Permit.S)
+		calldata, err := abi.Pack("permit", common.HexToAddress(opts.Permit.Spender), opts.TokenID, opts.Permit.Deadline, uint8(opts.Permit.V), common.HexToHash(opts.Permit.R), common.HexToHash(opts.Permit.S))
 		if err != nil {
 			return nil, err
 		}
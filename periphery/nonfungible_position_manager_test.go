@@ -34,7 +34,7 @@ func TestCreateCallParameters(t *testing.T) {
 	params, err := CreateCallParameters(pool01T)
 	assert.NoError(t, err)
 	assert.Equal(t, "0x13ead5620000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000280000000000000000000000000000000000000001000000000000000000000000", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x00", utils.ToHex(params.Value))
+	assert.Equal(t, "0x0", utils.ToHex(params.Value))
 }
 
 func TestAddCallParameters(t *testing.T) {
@@ -84,7 +84,7 @@ func TestAddCallParameters(t *testing.T) {
 	params, err := AddCallParameters(pos, opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "0x88316456000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000028fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff8000000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000003000000000000000000000000000000000000000000000000000000000000007b", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x00", utils.ToHex(params.Value))
+	assert.Equal(t, "0x0", utils.ToHex(params.Value))
 
 	// succeeds for increase
 	pos, err = entities.NewPosition(pool01T, big.NewInt(1), -constants.TickSpacings[constants.Fee004], constants.TickSpacings[constants.Fee004])
@@ -101,7 +101,7 @@ func TestAddCallParameters(t *testing.T) {
 	params, err = AddCallParameters(pos, opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "0x219f5d1700000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000007b", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x00", utils.ToHex(params.Value))
+	assert.Equal(t, "0x0", utils.ToHex(params.Value))
 
 	// createPool
 	pos, err = entities.NewPosition(pool01T, big.NewInt(1), -constants.TickSpacings[constants.Fee004], constants.TickSpacings[constants.Fee004])
@@ -119,7 +119,7 @@ func TestAddCallParameters(t *testing.T) {
 	params, err = AddCallParameters(pos, opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "0xac9650d80000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000008413ead562000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000028000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000016488316456000000000000000000000000000000000000000000000000000000000000000100000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000028fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff8000000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000003000000000000000000000000000000000000000000000000000000000000007b00000000000000000000000000000000000000000000000000000000", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x00", utils.ToHex(params.Value))
+	assert.Equal(t, "0x0", utils.ToHex(params.Value))
 
 	// useNative
 	pos, err = entities.NewPosition(pool1wethT, big.NewInt(1), -constants.TickSpacings[constants.Fee004], constants.TickSpacings[constants.Fee004])
@@ -137,7 +137,7 @@ func TestAddCallParameters(t *testing.T) {
 	params, err = AddCallParameters(pos, opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "0xac9650d800000000000000000000000000000000000000000000000000000000000000200000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000004000000000000000000000000000000000000000000000000000000000000001e00000000000000000000000000000000000000000000000000000000000000164883164560000000000000000000000000000000000000000000000000000000000000002000000000000000000000000c02aaa39b223fe8d0a0e5c4f27ead9083c756cc20000000000000000000000000000000000000000000000000000000000000028fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff8000000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000000000000000000000000000000010000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000003000000000000000000000000000000000000000000000000000000000000007b00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000412210e8a00000000000000000000000000000000000000000000000000000000", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x01", utils.ToHex(params.Value))
+	assert.Equal(t, "0x1", utils.ToHex(params.Value))
 }
 
 func TestCollectCallParameters(t *testing.T) {
@@ -151,7 +151,7 @@ func TestCollectCallParameters(t *testing.T) {
 	params, err := CollectCallParameters(opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "0xfc6f78650000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000300000000000000000000000000000000ffffffffffffffffffffffffffffffff00000000000000000000000000000000ffffffffffffffffffffffffffffffff", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x00", utils.ToHex(params.Value))
+	assert.Equal(t, "0x0", utils.ToHex(params.Value))
 
 	// works with eth
 	opts = &CollectOptions{
@@ -165,7 +165,7 @@ func TestCollectCallParameters(t *testing.T) {
 	params, err = CollectCallParameters(opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "0xac9650d8000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000030000000000000000000000000000000000000000000000000000000000000060000000000000000000000000000000000000000000000000000000000000012000000000000000000000000000000000000000000000000000000000000001a00000000000000000000000000000000000000000000000000000000000000084fc6f78650000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000ffffffffffffffffffffffffffffffff00000000000000000000000000000000ffffffffffffffffffffffffffffffff00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000004449404b7c00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000003000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000064df2ab5bb00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000300000000000000000000000000000000000000000000000000000000", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x00", utils.ToHex(params.Value))
+	assert.Equal(t, "0x0", utils.ToHex(params.Value))
 }
 
 func TestRemoveCallParameters(t *testing.T) {
@@ -238,7 +238,7 @@ func TestRemoveCallParameters(t *testing.T) {
 	params, err := RemoveCallParameters(pos, opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "0xac9650d8000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000040000000000000000000000000000000000000000000000000000000000000012000000000000000000000000000000000000000000000000000000000000000a40c49ccbe0000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000006400000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000007b000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000084fc6f78650000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000300000000000000000000000000000000ffffffffffffffffffffffffffffffff00000000000000000000000000000000ffffffffffffffffffffffffffffffff00000000000000000000000000000000000000000000000000000000", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x00", utils.ToHex(params.Value))
+	assert.Equal(t, "0x0", utils.ToHex(params.Value))
 
 	// works for partial
 	pos, err = entities.NewPosition(pool01T, big.NewInt(100), -constants.TickSpacings[constants.Fee004], constants.TickSpacings[constants.Fee004])
@@ -257,7 +257,7 @@ func TestRemoveCallParameters(t *testing.T) {
 	params, err = RemoveCallParameters(pos, opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "0xac9650d8000000000000000000000000000000000000000000000000000000000000002000000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000040000000000000000000000000000000000000000000000000000000000000012000000000000000000000000000000000000000000000000000000000000000a40c49ccbe0000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000003200000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000007b000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000084fc6f78650000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000300000000000000000000000000000000ffffffffffffffffffffffffffffffff00000000000000000000000000000000ffffffffffffffffffffffffffffffff00000000000000000000000000000000000000000000000000000000", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x00", utils.ToHex(params.Value))
+	assert.Equal(t, "0x0", utils.ToHex(params.Value))
 
 	// works with eth
 	ethAmount := core.FromRawAmount(core.EtherOnChain(1), big.NewInt(0))
@@ -297,7 +297,7 @@ func TestRemoveCallParameters(t *testing.T) {
 	params, err = RemoveCallParameters(pos, opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "0xac9650d80000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000800000000000000000000000000000000000000000000000000000000000000160000000000000000000000000000000000000000000000000000000000000022000000000000000000000000000000000000000000000000000000000000002a000000000000000000000000000000000000000000000000000000000000000a40c49ccbe0000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000006400000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000007b000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000084fc6f78650000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000ffffffffffffffffffffffffffffffff00000000000000000000000000000000ffffffffffffffffffffffffffffffff00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000004449404b7c00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000003000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000064df2ab5bb00000000000000000000000000000000000000000000000000000000000000020000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000300000000000000000000000000000000000000000000000000000000", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x00", utils.ToHex(params.Value))
+	assert.Equal(t, "0x0", utils.ToHex(params.Value))
 }
 
 func TestSafeTransferFromParameters(t *testing.T) {
@@ -310,7 +310,7 @@ func TestSafeTransferFromParameters(t *testing.T) {
 	params, err := SafeTransferFromParameters(opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "0x42842e0e000000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000030000000000000000000000000000000000000000000000000000000000000001", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x00", utils.ToHex(params.Value))
+	assert.Equal(t, "0x0", utils.ToHex(params.Value))
 
 	// succeeds data param
 	opts = &SafeTransferOptions{
@@ -322,5 +322,5 @@ func TestSafeTransferFromParameters(t *testing.T) {
 	params, err = SafeTransferFromParameters(opts)
 	assert.NoError(t, err)
 	assert.Equal(t, "0xb88d4fde000000000000000000000000000000000000000000000000000000000000000400000000000000000000000000000000000000000000000000000000000000030000000000000000000000000000000000000000000000000000000000000001000000000000000000000000000000000000000000000000000000000000008000000000000000000000000000000000000000000000000000000000000000140000000000000000000000000000000000009004000000000000000000000000", hexutil.Encode(params.Calldata))
-	assert.Equal(t, "0x00", utils.ToHex(params.Value))
+	assert.Equal(t, "0x0", utils.ToHex(params.Value))
 }
@@ -0,0 +1,194 @@
+package periphery
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	ErrInvalidSignatureLength = errors.New("invalid signature length")
+)
+
+var (
+	eip712DomainTypeHash  = crypto.Keccak256Hash([]byte("EIP712Domain(string name,string version,uint256 chainId,address verifyingContract)"))
+	permitTypeHash        = crypto.Keccak256Hash([]byte("Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)"))
+	allowedPermitTypeHash = crypto.Keccak256Hash([]byte("Permit(address holder,address spender,uint256 nonce,uint256 expiry,bool allowed)"))
+	nftPermitTypeHash     = crypto.Keccak256Hash([]byte("Permit(address spender,uint256 tokenId,uint256 nonce,uint256 deadline)"))
+)
+
+// The EIP-712 domain of the contract verifying a permit
+type EIP712Domain struct {
+	Name              string         // The name of the token or of the position manager NFT
+	Version           string         // The version of the domain, usually "1"
+	ChainID           *big.Int       // The chain the contract is deployed on
+	VerifyingContract common.Address // The token or position manager
+}
+
+// Separator returns the domain separator, as returned by the DOMAIN_SEPARATOR view of the contract
+func (d *EIP712Domain) Separator() common.Hash {
+	return hashStruct(
+		eip712DomainTypeHash,
+		crypto.Keccak256([]byte(d.Name)),
+		crypto.Keccak256([]byte(d.Version)),
+		math.U256Bytes(new(big.Int).Set(d.ChainID)),
+		common.LeftPadBytes(d.VerifyingContract.Bytes(), 32),
+	)
+}
+
+// The message of an EIP-2612 permit
+type Permit struct {
+	Owner    common.Address
+	Spender  common.Address
+	Value    *big.Int
+	Nonce    *big.Int
+	Deadline *big.Int
+}
+
+// Digest returns the EIP-712 digest the owner signs
+func (p *Permit) Digest(domain *EIP712Domain) common.Hash {
+	return typedDataDigest(domain, hashStruct(
+		permitTypeHash,
+		common.LeftPadBytes(p.Owner.Bytes(), 32),
+		common.LeftPadBytes(p.Spender.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(p.Value)),
+		math.U256Bytes(new(big.Int).Set(p.Nonce)),
+		math.U256Bytes(new(big.Int).Set(p.Deadline)),
+	))
+}
+
+// Sign signs the permit with the owner's key and returns the arguments expected by EncodeStandardPermit
+func (p *Permit) Sign(domain *EIP712Domain, key *ecdsa.PrivateKey) (*StandardPermitArguments, error) {
+	sig, err := SignDigest(p.Digest(domain), key)
+	if err != nil {
+		return nil, err
+	}
+	return sig.StandardPermitArguments(p.Value, p.Deadline), nil
+}
+
+// The message of a DAI-style permit, which allows the spender an unlimited amount or revokes the allowance
+type AllowedPermit struct {
+	Holder  common.Address
+	Spender common.Address
+	Nonce   *big.Int
+	Expiry  *big.Int
+	Allowed bool
+}
+
+// Digest returns the EIP-712 digest the holder signs
+func (p *AllowedPermit) Digest(domain *EIP712Domain) common.Hash {
+	allowed := big.NewInt(0)
+	if p.Allowed {
+		allowed = big.NewInt(1)
+	}
+	return typedDataDigest(domain, hashStruct(
+		allowedPermitTypeHash,
+		common.LeftPadBytes(p.Holder.Bytes(), 32),
+		common.LeftPadBytes(p.Spender.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(p.Nonce)),
+		math.U256Bytes(new(big.Int).Set(p.Expiry)),
+		math.U256Bytes(allowed),
+	))
+}
+
+// Sign signs the permit with the holder's key and returns the arguments expected by EncodeAllowedPermit
+func (p *AllowedPermit) Sign(domain *EIP712Domain, key *ecdsa.PrivateKey) (*AllowedPermitArguments, error) {
+	sig, err := SignDigest(p.Digest(domain), key)
+	if err != nil {
+		return nil, err
+	}
+	return sig.AllowedPermitArguments(p.Nonce, p.Expiry), nil
+}
+
+// The message of an ERC-721 permit of the position manager, the nonce is the one stored in the position
+type NFTPermit struct {
+	Spender  common.Address
+	TokenID  *big.Int
+	Nonce    *big.Int
+	Deadline *big.Int
+}
+
+// Digest returns the EIP-712 digest the owner of the NFT signs
+func (p *NFTPermit) Digest(domain *EIP712Domain) common.Hash {
+	return typedDataDigest(domain, hashStruct(
+		nftPermitTypeHash,
+		common.LeftPadBytes(p.Spender.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(p.TokenID)),
+		math.U256Bytes(new(big.Int).Set(p.Nonce)),
+		math.U256Bytes(new(big.Int).Set(p.Deadline)),
+	))
+}
+
+// Sign signs the permit with the NFT owner's key and returns the options expected by RemoveCallParameters
+func (p *NFTPermit) Sign(domain *EIP712Domain, key *ecdsa.PrivateKey) (*NFTPermitOptions, error) {
+	sig, err := SignDigest(p.Digest(domain), key)
+	if err != nil {
+		return nil, err
+	}
+	return sig.NFTPermitOptions(p.Spender, p.Deadline), nil
+}
+
+// A secp256k1 signature split into the components permit functions take
+type Signature struct {
+	V uint8 // The recovery id, 27 or 28
+	R [32]byte
+	S [32]byte
+}
+
+/**
+ * Splits a 65 byte signature
+ * @param sig the signature as r || s || v, v being either 0/1 or 27/28
+ */
+func SplitSignature(sig []byte) (*Signature, error) {
+	if len(sig) != crypto.SignatureLength {
+		return nil, ErrInvalidSignatureLength
+	}
+	var s Signature
+	copy(s.R[:], sig[:32])
+	copy(s.S[:], sig[32:64])
+	s.V = sig[64]
+	if s.V < 27 {
+		s.V += 27
+	}
+	return &s, nil
+}
+
+// SignDigest signs an EIP-712 digest with the given key
+func SignDigest(digest common.Hash, key *ecdsa.PrivateKey) (*Signature, error) {
+	sig, err := crypto.Sign(digest.Bytes(), key)
+	if err != nil {
+		return nil, err
+	}
+	return SplitSignature(sig)
+}
+
+func (s *Signature) StandardPermitArguments(amount, deadline *big.Int) *StandardPermitArguments {
+	return &StandardPermitArguments{V: s.V, R: s.R, S: s.S, Amount: amount, Deadline: deadline}
+}
+
+func (s *Signature) AllowedPermitArguments(nonce, expiry *big.Int) *AllowedPermitArguments {
+	return &AllowedPermitArguments{V: s.V, R: s.R, S: s.S, Nonce: nonce, Expiry: expiry}
+}
+
+func (s *Signature) NFTPermitOptions(spender common.Address, deadline *big.Int) *NFTPermitOptions {
+	return &NFTPermitOptions{
+		V:        uint(s.V),
+		R:        hexutil.Encode(s.R[:]),
+		S:        hexutil.Encode(s.S[:]),
+		Deadline: deadline,
+		Spender:  spender.Hex(),
+	}
+}
+
+func hashStruct(typeHash common.Hash, fields ...[]byte) common.Hash {
+	return crypto.Keccak256Hash(append([][]byte{typeHash.Bytes()}, fields...)...)
+}
+
+func typedDataDigest(domain *EIP712Domain, structHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domain.Separator().Bytes(), structHash.Bytes())
+}
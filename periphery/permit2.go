@@ -0,0 +1,309 @@
+package periphery
+
+import (
+	"crypto/ecdsa"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+//go:embed contracts/permit2/Permit2.sol/Permit2.json
+var permit2ABI []byte
+
+// The address Permit2 is deployed at on every chain
+var Permit2Address = common.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+
+var (
+	ErrPermit2TokenMismatch   = errors.New("permit2 permit is not for the token spent")
+	ErrPermit2SpenderMismatch = errors.New("permit2 permit is not for the contract spending the token")
+)
+
+// The signatures of the Permit2 permit overloads, which go-ethereum names after their order in the ABI
+const (
+	permit2PermitSingleSig = "permit(address,((address,uint160,uint48,uint48),address,uint256),bytes)"
+	permit2PermitBatchSig  = "permit(address,((address,uint160,uint48,uint48)[],address,uint256),bytes)"
+)
+
+const (
+	permitDetailsType    = "PermitDetails(address token,uint160 amount,uint48 expiration,uint48 nonce)"
+	tokenPermissionsType = "TokenPermissions(address token,uint256 amount)"
+)
+
+var (
+	permit2DomainTypeHash       = crypto.Keccak256Hash([]byte("EIP712Domain(string name,uint256 chainId,address verifyingContract)"))
+	permitDetailsTypeHash       = crypto.Keccak256Hash([]byte(permitDetailsType))
+	permitSingleTypeHash        = crypto.Keccak256Hash([]byte("PermitSingle(PermitDetails details,address spender,uint256 sigDeadline)" + permitDetailsType))
+	permitBatchTypeHash         = crypto.Keccak256Hash([]byte("PermitBatch(PermitDetails[] details,address spender,uint256 sigDeadline)" + permitDetailsType))
+	tokenPermissionsTypeHash    = crypto.Keccak256Hash([]byte(tokenPermissionsType))
+	permitTransferFromTypeHash  = crypto.Keccak256Hash([]byte("PermitTransferFrom(TokenPermissions permitted,address spender,uint256 nonce,uint256 deadline)" + tokenPermissionsType))
+	permitBatchTransferTypeHash = crypto.Keccak256Hash([]byte("PermitBatchTransferFrom(TokenPermissions[] permitted,address spender,uint256 nonce,uint256 deadline)" + tokenPermissionsType))
+)
+
+/**
+ * Returns the domain separator of a Permit2 deployment. Unlike token permits, the Permit2 domain has no version.
+ * @param chainID the chain Permit2 is deployed on
+ * @param permit2 the address of the deployment, usually Permit2Address
+ */
+func Permit2DomainSeparator(chainID *big.Int, permit2 common.Address) common.Hash {
+	return hashStruct(
+		permit2DomainTypeHash,
+		crypto.Keccak256([]byte("Permit2")),
+		math.U256Bytes(new(big.Int).Set(chainID)),
+		common.LeftPadBytes(permit2.Bytes(), 32),
+	)
+}
+
+// The allowance granted for one token by an allowance transfer permit
+type PermitDetails struct {
+	Token      common.Address
+	Amount     *big.Int // uint160
+	Expiration *big.Int // uint48, the timestamp at which the allowance expires
+	Nonce      *big.Int // uint48, the allowance nonce of the owner, token and spender
+}
+
+func (d *PermitDetails) hash() []byte {
+	return hashStruct(
+		permitDetailsTypeHash,
+		common.LeftPadBytes(d.Token.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(d.Amount)),
+		math.U256Bytes(new(big.Int).Set(d.Expiration)),
+		math.U256Bytes(new(big.Int).Set(d.Nonce)),
+	).Bytes()
+}
+
+// An allowance transfer permit for a single token
+type PermitSingle struct {
+	Details     PermitDetails
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// Digest returns the EIP-712 digest the owner signs
+func (p *PermitSingle) Digest(chainID *big.Int, permit2 common.Address) common.Hash {
+	return permit2Digest(chainID, permit2, hashStruct(
+		permitSingleTypeHash,
+		p.Details.hash(),
+		common.LeftPadBytes(p.Spender.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(p.SigDeadline)),
+	))
+}
+
+// Sign signs the permit with the owner's key
+func (p *PermitSingle) Sign(chainID *big.Int, permit2 common.Address, key *ecdsa.PrivateKey) ([]byte, error) {
+	return signPermit2(p.Digest(chainID, permit2), key)
+}
+
+// An allowance transfer permit for several tokens
+type PermitBatch struct {
+	Details     []PermitDetails
+	Spender     common.Address
+	SigDeadline *big.Int
+}
+
+// Digest returns the EIP-712 digest the owner signs
+func (p *PermitBatch) Digest(chainID *big.Int, permit2 common.Address) common.Hash {
+	var details [][]byte
+	for i := range p.Details {
+		details = append(details, p.Details[i].hash())
+	}
+	return permit2Digest(chainID, permit2, hashStruct(
+		permitBatchTypeHash,
+		crypto.Keccak256(details...),
+		common.LeftPadBytes(p.Spender.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(p.SigDeadline)),
+	))
+}
+
+// Sign signs the permit with the owner's key
+func (p *PermitBatch) Sign(chainID *big.Int, permit2 common.Address, key *ecdsa.PrivateKey) ([]byte, error) {
+	return signPermit2(p.Digest(chainID, permit2), key)
+}
+
+// The token and amount a signature transfer permits
+type TokenPermissions struct {
+	Token  common.Address
+	Amount *big.Int
+}
+
+func (t *TokenPermissions) hash() []byte {
+	return hashStruct(
+		tokenPermissionsTypeHash,
+		common.LeftPadBytes(t.Token.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(t.Amount)),
+	).Bytes()
+}
+
+// A one time signature transfer permit
+type PermitTransferFrom struct {
+	Permitted TokenPermissions
+	Spender   common.Address // The contract allowed to execute the transfer, it is signed but not part of the calldata
+	Nonce     *big.Int       // The unordered nonce, see Permit2's nonceBitmap
+	Deadline  *big.Int
+}
+
+// Digest returns the EIP-712 digest the owner signs
+func (p *PermitTransferFrom) Digest(chainID *big.Int, permit2 common.Address) common.Hash {
+	return permit2Digest(chainID, permit2, hashStruct(
+		permitTransferFromTypeHash,
+		p.Permitted.hash(),
+		common.LeftPadBytes(p.Spender.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(p.Nonce)),
+		math.U256Bytes(new(big.Int).Set(p.Deadline)),
+	))
+}
+
+// Sign signs the permit with the owner's key
+func (p *PermitTransferFrom) Sign(chainID *big.Int, permit2 common.Address, key *ecdsa.PrivateKey) ([]byte, error) {
+	return signPermit2(p.Digest(chainID, permit2), key)
+}
+
+// A one time signature transfer permit for several tokens
+type PermitBatchTransferFrom struct {
+	Permitted []TokenPermissions
+	Spender   common.Address
+	Nonce     *big.Int
+	Deadline  *big.Int
+}
+
+// Digest returns the EIP-712 digest the owner signs
+func (p *PermitBatchTransferFrom) Digest(chainID *big.Int, permit2 common.Address) common.Hash {
+	var permitted [][]byte
+	for i := range p.Permitted {
+		permitted = append(permitted, p.Permitted[i].hash())
+	}
+	return permit2Digest(chainID, permit2, hashStruct(
+		permitBatchTransferTypeHash,
+		crypto.Keccak256(permitted...),
+		common.LeftPadBytes(p.Spender.Bytes(), 32),
+		math.U256Bytes(new(big.Int).Set(p.Nonce)),
+		math.U256Bytes(new(big.Int).Set(p.Deadline)),
+	))
+}
+
+// Sign signs the permit with the owner's key
+func (p *PermitBatchTransferFrom) Sign(chainID *big.Int, permit2 common.Address, key *ecdsa.PrivateKey) ([]byte, error) {
+	return signPermit2(p.Digest(chainID, permit2), key)
+}
+
+// EncodePermit2PermitSingle encodes the Permit2 call setting the allowance signed in a single permit
+func EncodePermit2PermitSingle(owner common.Address, permit *PermitSingle, signature []byte) ([]byte, error) {
+	return packPermit2Method(permit2PermitSingleSig, owner, permit, signature)
+}
+
+// EncodePermit2PermitBatch encodes the Permit2 call setting the allowances signed in a batch permit
+func EncodePermit2PermitBatch(owner common.Address, permit *PermitBatch, signature []byte) ([]byte, error) {
+	return packPermit2Method(permit2PermitBatchSig, owner, permit, signature)
+}
+
+/**
+ * Encodes the Permit2 call transferring tokens with a signature transfer permit, which must be sent by the permit's
+ * spender
+ * @param owner the owner of the tokens, who signed the permit
+ * @param permit the signed permit
+ * @param to the recipient of the tokens
+ * @param requestedAmount the amount to transfer, at most the permitted amount
+ * @param signature the owner's signature
+ */
+func EncodePermit2TransferFrom(owner common.Address, permit *PermitTransferFrom, to common.Address, requestedAmount *big.Int, signature []byte) ([]byte, error) {
+	return GetABI(permit2ABI).Pack("permitTransferFrom",
+		struct {
+			Permitted TokenPermissions
+			Nonce     *big.Int
+			Deadline  *big.Int
+		}{permit.Permitted, permit.Nonce, permit.Deadline},
+		struct {
+			To              common.Address
+			RequestedAmount *big.Int
+		}{to, requestedAmount},
+		owner,
+		signature,
+	)
+}
+
+// EncodePermit2Approve encodes the Permit2 call directly granting an allowance to a spender, sent by the owner
+func EncodePermit2Approve(token, spender common.Address, amount, expiration *big.Int) ([]byte, error) {
+	return GetABI(permit2ABI).Pack("approve", token, spender, amount, expiration)
+}
+
+// A signed Permit2 allowance, for a contract that pulls the token through Permit2
+type Permit2PermitArguments struct {
+	Owner     common.Address
+	Permit    *PermitSingle
+	Signature []byte
+	Permit2   common.Address // The Permit2 deployment, Permit2Address if zero
+}
+
+// A signed Permit2 allowance for a builder to execute before its operation
+type Permit2Options struct {
+	*Permit2PermitArguments
+	Spender common.Address // The contract the calldata is sent to, which must pull the token through Permit2 and which the permit must be for
+}
+
+/**
+ * Builds the Permit2 permit call to execute before a contract spends the token. Only contracts that pull tokens through
+ * Permit2 can use the allowance.
+ * @param token the token spent
+ * @param spender the contract spending the token, which the permit must be for
+ * @param args the signed permit
+ */
+func Permit2PreCall(token common.Address, spender common.Address, args *Permit2PermitArguments) (*utils.ContractCall, error) {
+	if args.Permit.Details.Token != token {
+		return nil, ErrPermit2TokenMismatch
+	}
+	if args.Permit.Spender != spender {
+		return nil, ErrPermit2SpenderMismatch
+	}
+	calldata, err := EncodePermit2PermitSingle(args.Owner, args.Permit, args.Signature)
+	if err != nil {
+		return nil, err
+	}
+	permit2 := args.Permit2
+	if permit2 == (common.Address{}) {
+		permit2 = Permit2Address
+	}
+	return &utils.ContractCall{To: permit2, Calldata: calldata, Value: big.NewInt(0)}, nil
+}
+
+// permit2Method returns the Permit2 method of the given signature
+func permit2Method(sig string) (abi.Method, error) {
+	for _, method := range GetABI(permit2ABI).Methods {
+		if method.Sig == sig {
+			return method, nil
+		}
+	}
+	return abi.Method{}, fmt.Errorf("permit2 method %s not found", sig)
+}
+
+func packPermit2Method(sig string, args ...interface{}) ([]byte, error) {
+	method, err := permit2Method(sig)
+	if err != nil {
+		return nil, err
+	}
+	arguments, err := method.Inputs.Pack(args...)
+	if err != nil {
+		return nil, err
+	}
+	return append(append([]byte(nil), method.ID...), arguments...), nil
+}
+
+// signPermit2 signs a digest, with v being 27 or 28 as Permit2 expects
+func signPermit2(digest common.Hash, key *ecdsa.PrivateKey) ([]byte, error) {
+	sig, err := crypto.Sign(digest.Bytes(), key)
+	if err != nil {
+		return nil, err
+	}
+	sig[crypto.RecoveryIDOffset] += 27
+	return sig, nil
+}
+
+func permit2Digest(chainID *big.Int, permit2 common.Address, structHash common.Hash) common.Hash {
+	return crypto.Keccak256Hash([]byte("\x19\x01"), Permit2DomainSeparator(chainID, permit2).Bytes(), structHash.Bytes())
+}
@@ -0,0 +1,265 @@
+package periphery
+
+import (
+	"math/big"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+)
+
+var (
+	permit2ChainID        = big.NewInt(1)
+	tokenPermissionsTypes = []apitypes.Type{
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+	}
+)
+
+// referencePermit2Digest computes the digest of the given Permit2 message with go-ethereum's generic EIP-712 encoder
+func referencePermit2Digest(t *testing.T, primaryType string, types apitypes.Types, message apitypes.TypedDataMessage) common.Hash {
+	types["EIP712Domain"] = []apitypes.Type{
+		{Name: "name", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	}
+	typedData := apitypes.TypedData{
+		Types:       types,
+		PrimaryType: primaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:              "Permit2",
+			ChainId:           (*math.HexOrDecimal256)(permit2ChainID),
+			VerifyingContract: Permit2Address.Hex(),
+		},
+		Message: message,
+	}
+	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	assert.NoError(t, err)
+	assert.Equal(t, hexutil.Bytes(Permit2DomainSeparator(permit2ChainID, Permit2Address).Bytes()), domainSeparator)
+	structHash, err := typedData.HashStruct(primaryType, typedData.Message)
+	assert.NoError(t, err)
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domainSeparator, structHash)
+}
+
+func assertPermit2SignedBy(t *testing.T, digest common.Hash, sig []byte) {
+	// Permit2 expects v to be 27 or 28, recovery expects 0 or 1
+	assert.Contains(t, []byte{27, 28}, sig[64])
+	recoverable := append([]byte(nil), sig...)
+	recoverable[64] -= 27
+	pub, err := crypto.SigToPub(digest.Bytes(), recoverable)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(permitKey.PublicKey), crypto.PubkeyToAddress(*pub))
+}
+
+// go-ethereum's EIP-712 encoder does not support uint160 and uint48, so the allowance transfer types are checked
+// against the constants of the Permit2 contract
+func TestPermit2TypeHashes(t *testing.T) {
+	assert.Equal(t, common.HexToHash("0x65626cad6cb96493bf6f5ebea28756c966f023ab9e8a83a7101849d5573b3678"), permitDetailsTypeHash)
+	assert.Equal(t, common.HexToHash("0xf3841cd1ff0085026a6327b620b67997ce40f282c88a8e905a7a5626e310f3d0"), permitSingleTypeHash)
+	assert.Equal(t, common.HexToHash("0xaf1b0d30d2cab0380e68f0689007e3254993c596f2fdd0aaa7f4d04f79440863"), permitBatchTypeHash)
+	assert.Equal(t, common.HexToHash("0x618358ac3db8dc274f0cd8829da7e234bd48cd73c4a740aede1adec9846d06a1"), tokenPermissionsTypeHash)
+	assert.Equal(t, common.HexToHash("0x939c21a48a8dbe3a9a2404a1d46691e4d39f6583d6ec6b35714604c986d80106"), permitTransferFromTypeHash)
+	assert.Equal(t, common.HexToHash("0xfcf35f5ac6a2c28868dc44c302166470266239195f02b0ee408334829333b766"), permitBatchTransferTypeHash)
+
+	// DOMAIN_SEPARATOR of the mainnet deployment
+	assert.Equal(t, common.HexToHash("0x866a5aba21966af95d6c7ab78eb2b2fc913915c28be3b9aa07cc04ff903e3f28"), Permit2DomainSeparator(permit2ChainID, Permit2Address))
+}
+
+func newPermitSingle(token common.Address) *PermitSingle {
+	return &PermitSingle{
+		Details: PermitDetails{
+			Token:      token,
+			Amount:     big.NewInt(1000),
+			Expiration: big.NewInt(456),
+			Nonce:      big.NewInt(2),
+		},
+		Spender:     recipientT,
+		SigDeadline: deadlineT,
+	}
+}
+
+func TestPermitSingle(t *testing.T) {
+	permit := newPermitSingle(token0.Address)
+	sig, err := permit.Sign(permit2ChainID, Permit2Address, permitKey)
+	assert.NoError(t, err)
+	assertPermit2SignedBy(t, permit.Digest(permit2ChainID, Permit2Address), sig)
+
+	owner := crypto.PubkeyToAddress(permitKey.PublicKey)
+	calldata, err := EncodePermit2PermitSingle(owner, permit, sig)
+	assert.NoError(t, err)
+	method, err := permit2Method("permit(address,((address,uint160,uint48,uint48),address,uint256),bytes)")
+	assert.NoError(t, err)
+	assert.Equal(t, method.ID, calldata[:4])
+	values, err := method.Inputs.Unpack(calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, owner, values[0])
+	assert.Equal(t, permit, abi.ConvertType(values[1], new(PermitSingle)))
+	assert.Equal(t, sig, values[2])
+}
+
+func TestPermitBatch(t *testing.T) {
+	permit := &PermitBatch{
+		Details: []PermitDetails{
+			{Token: token0.Address, Amount: big.NewInt(1000), Expiration: big.NewInt(456), Nonce: big.NewInt(2)},
+			{Token: token1.Address, Amount: big.NewInt(2000), Expiration: big.NewInt(789), Nonce: big.NewInt(3)},
+		},
+		Spender:     recipientT,
+		SigDeadline: deadlineT,
+	}
+	sig, err := permit.Sign(permit2ChainID, Permit2Address, permitKey)
+	assert.NoError(t, err)
+	assertPermit2SignedBy(t, permit.Digest(permit2ChainID, Permit2Address), sig)
+
+	calldata, err := EncodePermit2PermitBatch(senderT, permit, sig)
+	assert.NoError(t, err)
+	method, err := permit2Method("permit(address,((address,uint160,uint48,uint48)[],address,uint256),bytes)")
+	assert.NoError(t, err)
+	assert.Equal(t, method.ID, calldata[:4])
+	values, err := method.Inputs.Unpack(calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, permit, abi.ConvertType(values[1], new(PermitBatch)))
+}
+
+func TestPermitTransferFrom(t *testing.T) {
+	permit := &PermitTransferFrom{
+		Permitted: TokenPermissions{Token: token0.Address, Amount: big.NewInt(1000)},
+		Spender:   recipientT,
+		Nonce:     big.NewInt(7),
+		Deadline:  deadlineT,
+	}
+	assert.Equal(t, referencePermit2Digest(t, "PermitTransferFrom", apitypes.Types{
+		"TokenPermissions": tokenPermissionsTypes,
+		"PermitTransferFrom": {
+			{Name: "permitted", Type: "TokenPermissions"},
+			{Name: "spender", Type: "address"},
+			{Name: "nonce", Type: "uint256"},
+			{Name: "deadline", Type: "uint256"},
+		},
+	}, apitypes.TypedDataMessage{
+		"permitted": map[string]interface{}{"token": token0.Address.Hex(), "amount": "1000"},
+		"spender":   recipientT.Hex(),
+		"nonce":     "7",
+		"deadline":  "123",
+	}), permit.Digest(permit2ChainID, Permit2Address))
+
+	batch := &PermitBatchTransferFrom{
+		Permitted: []TokenPermissions{permit.Permitted, {Token: token1.Address, Amount: big.NewInt(5)}},
+		Spender:   recipientT,
+		Nonce:     big.NewInt(7),
+		Deadline:  deadlineT,
+	}
+	assert.Equal(t, referencePermit2Digest(t, "PermitBatchTransferFrom", apitypes.Types{
+		"TokenPermissions": tokenPermissionsTypes,
+		"PermitBatchTransferFrom": {
+			{Name: "permitted", Type: "TokenPermissions[]"},
+			{Name: "spender", Type: "address"},
+			{Name: "nonce", Type: "uint256"},
+			{Name: "deadline", Type: "uint256"},
+		},
+	}, apitypes.TypedDataMessage{
+		"permitted": []interface{}{
+			map[string]interface{}{"token": token0.Address.Hex(), "amount": "1000"},
+			map[string]interface{}{"token": token1.Address.Hex(), "amount": "5"},
+		},
+		"spender":  recipientT.Hex(),
+		"nonce":    "7",
+		"deadline": "123",
+	}), batch.Digest(permit2ChainID, Permit2Address))
+
+	sig, err := permit.Sign(permit2ChainID, Permit2Address, permitKey)
+	assert.NoError(t, err)
+	assertPermit2SignedBy(t, permit.Digest(permit2ChainID, Permit2Address), sig)
+	batchSig, err := batch.Sign(permit2ChainID, Permit2Address, permitKey)
+	assert.NoError(t, err)
+	assertPermit2SignedBy(t, batch.Digest(permit2ChainID, Permit2Address), batchSig)
+
+	calldata, err := EncodePermit2TransferFrom(senderT, permit, recipientT, big.NewInt(600), sig)
+	assert.NoError(t, err)
+	values, err := GetABI(permit2ABI).Methods["permitTransferFrom"].Inputs.Unpack(calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, senderT, values[2])
+	assert.Equal(t, sig, values[3])
+}
+
+func TestPermit2PreCall(t *testing.T) {
+	owner := crypto.PubkeyToAddress(permitKey.PublicKey)
+	permit := newPermitSingle(token0.Address)
+	sig, err := permit.Sign(permit2ChainID, Permit2Address, permitKey)
+	assert.NoError(t, err)
+	args := &Permit2PermitArguments{Owner: owner, Permit: permit, Signature: sig}
+	expected, err := EncodePermit2PermitSingle(owner, permit, sig)
+	assert.NoError(t, err)
+
+	call, err := Permit2PreCall(token0.Address, recipientT, args)
+	assert.NoError(t, err)
+	assert.Equal(t, Permit2Address, call.To)
+	assert.Equal(t, expected, call.Calldata)
+
+	deployment := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	call, err = Permit2PreCall(token0.Address, recipientT, &Permit2PermitArguments{Owner: owner, Permit: permit, Signature: sig, Permit2: deployment})
+	assert.NoError(t, err)
+	assert.Equal(t, deployment, call.To)
+
+	// the permit must be for the token spent and the contract spending it
+	_, err = Permit2PreCall(token1.Address, recipientT, args)
+	assert.ErrorIs(t, err, ErrPermit2TokenMismatch)
+	_, err = Permit2PreCall(token0.Address, senderT, args)
+	assert.ErrorIs(t, err, ErrPermit2SpenderMismatch)
+
+	// the builders return the permit as a call to make before their own
+	trade, err := entities.FromRoute(route_0_1, core.FromRawAmount(token0, big.NewInt(100)), core.ExactInput)
+	assert.NoError(t, err)
+	swapOptions := &SwapOptions{
+		SlippageTolerance: slippageToleranceT,
+		Recipient:         recipientT,
+		Deadline:          deadlineT,
+		InputTokenPermit2: &Permit2Options{Permit2PermitArguments: args, Spender: recipientT},
+	}
+	params, err := SwapCallParameters([]*entities.Trade{trade}, swapOptions)
+	assert.NoError(t, err)
+	assert.Len(t, params.PreCalls, 1)
+	assert.Equal(t, Permit2Address, params.PreCalls[0].To)
+	assert.Equal(t, expected, params.PreCalls[0].Calldata)
+
+	swapOptions.InputTokenPermit2.Spender = senderT
+	_, err = SwapCallParameters([]*entities.Trade{trade}, swapOptions)
+	assert.ErrorIs(t, err, ErrPermit2SpenderMismatch)
+
+	permit1 := newPermitSingle(token1T.Address)
+	sig1, err := permit1.Sign(permit2ChainID, deployment, permitKey)
+	assert.NoError(t, err)
+	expected1, err := EncodePermit2PermitSingle(owner, permit1, sig1)
+	assert.NoError(t, err)
+	pos, err := entities.NewPosition(pool01T, big.NewInt(1), -constants.TickSpacings[feeT], constants.TickSpacings[feeT])
+	assert.NoError(t, err)
+	addOptions := &AddLiquidityOptions{
+		MintSpecificOptions: &MintSpecificOptions{Recipient: recipientT},
+		CommonAddLiquidityOptions: &CommonAddLiquidityOptions{
+			SlippageTolerance: slippageToleranceT,
+			Deadline:          deadlineT,
+			Token1Permit2: &Permit2Options{
+				Permit2PermitArguments: &Permit2PermitArguments{Owner: owner, Permit: permit1, Signature: sig1, Permit2: deployment},
+				Spender:                recipientT,
+			},
+		},
+	}
+	params, err = AddCallParameters(pos, addOptions)
+	assert.NoError(t, err)
+	assert.Len(t, params.PreCalls, 1)
+	assert.Equal(t, deployment, params.PreCalls[0].To)
+	assert.Equal(t, expected1, params.PreCalls[0].Calldata)
+
+	// a permit for token1 cannot be used for token0
+	addOptions.Token0Permit2 = addOptions.Token1Permit2
+	_, err = AddCallParameters(pos, addOptions)
+	assert.ErrorIs(t, err, ErrPermit2TokenMismatch)
+}
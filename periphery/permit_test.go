@@ -0,0 +1,191 @@
+package periphery
+
+import (
+	"math/big"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+)
+
+var (
+	permitKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	permitDomain = &EIP712Domain{
+		Name:              "Token",
+		Version:           "1",
+		ChainID:           big.NewInt(1),
+		VerifyingContract: common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+	}
+)
+
+// referenceDigest computes the digest of the given message with go-ethereum's generic EIP-712 encoder
+func referenceDigest(t *testing.T, primaryType string, fields []apitypes.Type, message apitypes.TypedDataMessage) common.Hash {
+	typedData := apitypes.TypedData{
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			primaryType: fields,
+		},
+		PrimaryType: primaryType,
+		Domain: apitypes.TypedDataDomain{
+			Name:              permitDomain.Name,
+			Version:           permitDomain.Version,
+			ChainId:           (*math.HexOrDecimal256)(permitDomain.ChainID),
+			VerifyingContract: permitDomain.VerifyingContract.Hex(),
+		},
+		Message: message,
+	}
+	domainSeparator, err := typedData.HashStruct("EIP712Domain", typedData.Domain.Map())
+	assert.NoError(t, err)
+	assert.Equal(t, hexutil.Bytes(permitDomain.Separator().Bytes()), domainSeparator)
+	structHash, err := typedData.HashStruct(primaryType, typedData.Message)
+	assert.NoError(t, err)
+	return crypto.Keccak256Hash([]byte("\x19\x01"), domainSeparator, structHash)
+}
+
+func assertSignedBy(t *testing.T, digest common.Hash, v uint8, r, s [32]byte) {
+	sig := append(append(r[:], s[:]...), v-27)
+	pub, err := crypto.SigToPub(digest.Bytes(), sig)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(permitKey.PublicKey), crypto.PubkeyToAddress(*pub))
+}
+
+func TestPermit(t *testing.T) {
+	permit := &Permit{
+		Owner:    crypto.PubkeyToAddress(permitKey.PublicKey),
+		Spender:  recipientT,
+		Value:    big.NewInt(100),
+		Nonce:    big.NewInt(2),
+		Deadline: deadlineT,
+	}
+	assert.Equal(t, referenceDigest(t, "Permit", []apitypes.Type{
+		{Name: "owner", Type: "address"},
+		{Name: "spender", Type: "address"},
+		{Name: "value", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	}, apitypes.TypedDataMessage{
+		"owner":    permit.Owner.Hex(),
+		"spender":  permit.Spender.Hex(),
+		"value":    "100",
+		"nonce":    "2",
+		"deadline": "123",
+	}), permit.Digest(permitDomain))
+
+	args, err := permit.Sign(permitDomain, permitKey)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(100), args.Amount)
+	assert.Equal(t, deadlineT, args.Deadline)
+	assertSignedBy(t, permit.Digest(permitDomain), args.V, args.R, args.S)
+}
+
+func TestAllowedPermit(t *testing.T) {
+	permit := &AllowedPermit{
+		Holder:  crypto.PubkeyToAddress(permitKey.PublicKey),
+		Spender: recipientT,
+		Nonce:   big.NewInt(3),
+		Expiry:  deadlineT,
+		Allowed: true,
+	}
+	assert.Equal(t, referenceDigest(t, "Permit", []apitypes.Type{
+		{Name: "holder", Type: "address"},
+		{Name: "spender", Type: "address"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "expiry", Type: "uint256"},
+		{Name: "allowed", Type: "bool"},
+	}, apitypes.TypedDataMessage{
+		"holder":  permit.Holder.Hex(),
+		"spender": permit.Spender.Hex(),
+		"nonce":   "3",
+		"expiry":  "123",
+		"allowed": true,
+	}), permit.Digest(permitDomain))
+
+	args, err := permit.Sign(permitDomain, permitKey)
+	assert.NoError(t, err)
+	assertSignedBy(t, permit.Digest(permitDomain), args.V, args.R, args.S)
+
+	calldata, err := EncodeAllowedPermit(token0, args)
+	assert.NoError(t, err)
+	call, err := DecodeCalldata(calldata)
+	assert.NoError(t, err)
+	assert.Equal(t, &SelfPermitAllowedArgs{Token: token0.Address, Nonce: big.NewInt(3), Expiry: deadlineT, V: args.V, R: args.R, S: args.S}, call.Args)
+}
+
+func TestNFTPermit(t *testing.T) {
+	permit := &NFTPermit{
+		Spender:  senderT,
+		TokenID:  tokenIDT,
+		Nonce:    big.NewInt(1),
+		Deadline: deadlineT,
+	}
+	assert.Equal(t, referenceDigest(t, "Permit", []apitypes.Type{
+		{Name: "spender", Type: "address"},
+		{Name: "tokenId", Type: "uint256"},
+		{Name: "nonce", Type: "uint256"},
+		{Name: "deadline", Type: "uint256"},
+	}, apitypes.TypedDataMessage{
+		"spender":  permit.Spender.Hex(),
+		"tokenId":  "1",
+		"nonce":    "1",
+		"deadline": "123",
+	}), permit.Digest(permitDomain))
+
+	opts, err := permit.Sign(permitDomain, permitKey)
+	assert.NoError(t, err)
+	assert.Equal(t, senderT.Hex(), opts.Spender)
+
+	// the signed permit can be sent along with an exit
+	pos, err := entities.NewPosition(pool01T, big.NewInt(100), -constants.TickSpacings[feeT], constants.TickSpacings[feeT])
+	assert.NoError(t, err)
+	params, err := RemoveCallParameters(pos, &RemoveLiquidityOptions{
+		TokenID:             tokenIDT,
+		LiquidityPercentage: core.NewPercent(big.NewInt(1), big.NewInt(1)),
+		SlippageTolerance:   slippageToleranceT,
+		Deadline:            deadlineT,
+		Permit:              opts,
+		CollectOptions: &CollectOptions{
+			ExpectedCurrencyOwed0: core.FromRawAmount(token0T, big.NewInt(0)),
+			ExpectedCurrencyOwed1: core.FromRawAmount(token1T, big.NewInt(0)),
+			Recipient:             recipientT,
+		},
+	})
+	assert.NoError(t, err)
+	calls, err := DecodeMulticall(params.Calldata)
+	assert.NoError(t, err)
+	assert.Equal(t, "permit", calls[0].Method)
+	args := calls[0].Args.(*NFTPermitArgs)
+	assert.Equal(t, senderT, args.Spender)
+	assert.Equal(t, tokenIDT, args.TokenId)
+	assertSignedBy(t, permit.Digest(permitDomain), args.V, args.R, args.S)
+}
+
+func TestSplitSignature(t *testing.T) {
+	sig := make([]byte, 65)
+	sig[0], sig[32], sig[64] = 1, 2, 1
+	split, err := SplitSignature(sig)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(28), split.V)
+	assert.Equal(t, byte(1), split.R[0])
+	assert.Equal(t, byte(2), split.S[0])
+
+	sig[64] = 27
+	split, err = SplitSignature(sig)
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(27), split.V)
+
+	_, err = SplitSignature(sig[:64])
+	assert.ErrorIs(t, err, ErrInvalidSignatureLength)
+}
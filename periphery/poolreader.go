@@ -0,0 +1,226 @@
+package periphery
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math/big"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+//go:embed contracts/lens/UniswapInterfaceMulticall.sol/UniswapInterfaceMulticall.json
+var interfaceMulticallABI []byte
+
+var (
+	ErrPoolCallFailed      = errors.New("pool call failed")
+	ErrPoolResultsMismatch = errors.New("number of results does not match the number of pool calls")
+	ErrPoolTokensMismatch  = errors.New("tokens do not match the pool state")
+)
+
+// poolStateMethods are the pool views read for each pool, in call order
+var poolStateMethods = []string{"token0", "token1", "swapFeeUnits", "tickDistance", "getPoolState", "getLiquidityState", "getFeeGrowthGlobal"}
+
+// The gas limit of each pool view call made by the multicall, when none is given
+var DefaultPoolCallGasLimit = big.NewInt(1_000_000)
+
+// The state of an Elastic pool read on chain
+type PoolState struct {
+	Address            common.Address
+	Token0             common.Address
+	Token1             common.Address
+	Fee                constants.FeeAmount
+	TickDistance       int
+	SqrtP              *big.Int
+	CurrentTick        int
+	NearestCurrentTick int
+	Locked             bool
+	BaseL              *big.Int
+	ReinvestL          *big.Int
+	ReinvestLLast      *big.Int
+	FeeGrowthGlobal    *big.Int
+	Err                error // Set when one of the pool's calls failed, in which case the other fields must not be used
+}
+
+/**
+ * Constructs the pool entity of the state
+ * @param token0 the token0 of the pool
+ * @param token1 the token1 of the pool
+ * @param ticks the tick data of the pool, e.g. loaded with DecodeElasticTicks
+ */
+func (s *PoolState) NewPool(token0, token1 *core.Token, ticks entities.TickDataProvider) (*entities.Pool, error) {
+	if s.Err != nil {
+		return nil, s.Err
+	}
+	if token0.Address != s.Token0 || token1.Address != s.Token1 {
+		return nil, ErrPoolTokensMismatch
+	}
+	return entities.NewPool(token0, token1, s.Fee, s.SqrtP, s.BaseL, s.ReinvestL, s.CurrentTick, ticks)
+}
+
+type multicallCall struct {
+	Target   common.Address
+	GasLimit *big.Int
+	CallData []byte
+}
+
+type multicallResult struct {
+	Success    bool
+	GasUsed    *big.Int
+	ReturnData []byte
+}
+
+/**
+ * Produces the calldata of a UniswapInterfaceMulticall aggregate reading the state of every pool
+ * @param pools the addresses of the pools to read
+ * @param gasLimit the gas limit of each call, DefaultPoolCallGasLimit if nil
+ */
+func PoolStateCallParameters(pools []common.Address, gasLimit *big.Int) (*utils.MethodParameters, error) {
+	if gasLimit == nil {
+		gasLimit = DefaultPoolCallGasLimit
+	}
+	abi := GetABI(poolStorageABI)
+	calls := make([]multicallCall, 0, len(pools)*len(poolStateMethods))
+	for _, pool := range pools {
+		for _, method := range poolStateMethods {
+			calls = append(calls, multicallCall{Target: pool, GasLimit: gasLimit, CallData: abi.Methods[method].ID})
+		}
+	}
+	calldata, err := GetABI(interfaceMulticallABI).Pack("multicall", calls)
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{
+		Calldata: calldata,
+		Value:    big.NewInt(0),
+	}, nil
+}
+
+/**
+ * Decodes the return data of an aggregate built by PoolStateCallParameters
+ * @param pools the addresses of the pools that were read, in the same order
+ * @param data the return data of the multicall
+ * @returns The block number the state was read at and the state of each pool. A pool whose calls failed has its Err set.
+ */
+func DecodePoolStates(pools []common.Address, data []byte) (*big.Int, []*PoolState, error) {
+	multicall := GetABI(interfaceMulticallABI).Methods["multicall"]
+	values, err := multicall.Outputs.Unpack(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	var out struct {
+		BlockNumber *big.Int
+		ReturnData  []multicallResult
+	}
+	if err := multicall.Outputs.Copy(&out, values); err != nil {
+		return nil, nil, err
+	}
+	if len(out.ReturnData) != len(pools)*len(poolStateMethods) {
+		return nil, nil, ErrPoolResultsMismatch
+	}
+
+	states := make([]*PoolState, len(pools))
+	for i, pool := range pools {
+		states[i] = decodePoolState(pool, out.ReturnData[i*len(poolStateMethods):(i+1)*len(poolStateMethods)])
+	}
+	return out.BlockNumber, states, nil
+}
+
+func decodePoolState(pool common.Address, results []multicallResult) *PoolState {
+	abi := GetABI(poolStorageABI)
+	state := &PoolState{Address: pool}
+	for i, method := range poolStateMethods {
+		if !results[i].Success {
+			state.Err = fmt.Errorf("%w: %s %s", ErrPoolCallFailed, pool, method)
+			return state
+		}
+		values, err := abi.Unpack(method, results[i].ReturnData)
+		if err != nil {
+			state.Err = fmt.Errorf("%w: %s %s: %v", ErrPoolCallFailed, pool, method, err)
+			return state
+		}
+		switch method {
+		case "token0":
+			state.Token0 = values[0].(common.Address)
+		case "token1":
+			state.Token1 = values[0].(common.Address)
+		case "swapFeeUnits":
+			state.Fee = constants.FeeAmount(values[0].(*big.Int).Uint64())
+		case "tickDistance":
+			state.TickDistance = int(values[0].(*big.Int).Int64())
+		case "getPoolState":
+			state.SqrtP = values[0].(*big.Int)
+			state.CurrentTick = int(values[1].(*big.Int).Int64())
+			state.NearestCurrentTick = int(values[2].(*big.Int).Int64())
+			state.Locked = values[3].(bool)
+		case "getLiquidityState":
+			state.BaseL = values[0].(*big.Int)
+			state.ReinvestL = values[1].(*big.Int)
+			state.ReinvestLLast = values[2].(*big.Int)
+		case "getFeeGrowthGlobal":
+			state.FeeGrowthGlobal = values[0].(*big.Int)
+		}
+	}
+	return state
+}
+
+// Options for reading pool states
+type PoolReadOptions struct {
+	BatchSize   int      // The maximum number of pools read per multicall, all pools are read at once if not positive
+	GasLimit    *big.Int // The gas limit of each pool call, DefaultPoolCallGasLimit if nil
+	BlockNumber *big.Int // The block to read at, the latest block if nil
+}
+
+/**
+ * Reads the state of every pool through a UniswapInterfaceMulticall deployment. When the pools are read in several
+ * batches, every batch is read at the block of the first one so the states are consistent.
+ * @param ctx the context of the calls
+ * @param caller the transport executing eth_call, e.g. an ethclient.Client or recorded responses in tests
+ * @param multicall the address of the multicall contract
+ * @param pools the addresses of the pools to read
+ * @param opts the optional batching, gas limit and block number
+ * @returns The block number the states were read at and the state of each pool, in the same order as pools
+ */
+func ReadPoolStates(ctx context.Context, caller ethereum.ContractCaller, multicall common.Address, pools []common.Address, opts *PoolReadOptions) (*big.Int, []*PoolState, error) {
+	if opts == nil {
+		opts = &PoolReadOptions{}
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(pools)
+	}
+
+	blockNumber := opts.BlockNumber
+	states := make([]*PoolState, 0, len(pools))
+	for start := 0; start < len(pools); start += batchSize {
+		end := start + batchSize
+		if end > len(pools) {
+			end = len(pools)
+		}
+		batch := pools[start:end]
+		params, err := PoolStateCallParameters(batch, opts.GasLimit)
+		if err != nil {
+			return nil, nil, err
+		}
+		data, err := caller.CallContract(ctx, ethereum.CallMsg{To: &multicall, Data: params.Calldata}, blockNumber)
+		if err != nil {
+			return nil, nil, err
+		}
+		readAt, batchStates, err := DecodePoolStates(batch, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		if blockNumber == nil {
+			blockNumber = readAt
+		}
+		states = append(states, batchStates...)
+	}
+	return blockNumber, states, nil
+}
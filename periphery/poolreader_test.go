@@ -0,0 +1,116 @@
+package periphery
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+// recordedCaller answers multicall aggregates from recorded pool view responses
+type recordedCaller struct {
+	t            *testing.T
+	responses    map[common.Address]map[string][]interface{}
+	blockNumbers []*big.Int
+}
+
+func (c *recordedCaller) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.blockNumbers = append(c.blockNumbers, blockNumber)
+	multicall := GetABI(interfaceMulticallABI).Methods["multicall"]
+	values, err := multicall.Inputs.Unpack(msg.Data[4:])
+	assert.NoError(c.t, err)
+	calls := *abi.ConvertType(values[0], new([]multicallCall)).(*[]multicallCall)
+
+	pool := GetABI(poolStorageABI)
+	var results []multicallResult
+	for _, call := range calls {
+		method, err := pool.MethodById(call.CallData)
+		assert.NoError(c.t, err)
+		response, ok := c.responses[call.Target][method.Name]
+		if !ok {
+			results = append(results, multicallResult{Success: false, GasUsed: big.NewInt(0), ReturnData: []byte{}})
+			continue
+		}
+		data, err := method.Outputs.Pack(response...)
+		assert.NoError(c.t, err)
+		results = append(results, multicallResult{Success: true, GasUsed: big.NewInt(100), ReturnData: data})
+	}
+	return multicall.Outputs.Pack(big.NewInt(1234), results)
+}
+
+func poolResponses(sqrtP *big.Int, tick int64) map[string][]interface{} {
+	return map[string][]interface{}{
+		"token0":             {token0.Address},
+		"token1":             {token1.Address},
+		"swapFeeUnits":       {big.NewInt(int64(feeAmount))},
+		"tickDistance":       {big.NewInt(int64(constants.TickSpacings[feeAmount]))},
+		"getPoolState":       {sqrtP, big.NewInt(tick), big.NewInt(utils.MinTick), false},
+		"getLiquidityState":  {big.NewInt(1_000_000), big.NewInt(10), big.NewInt(9)},
+		"getFeeGrowthGlobal": {big.NewInt(42)},
+	}
+}
+
+func TestReadPoolStates(t *testing.T) {
+	poolA := common.HexToAddress("0x00000000000000000000000000000000000000a0")
+	poolB := common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	broken := common.HexToAddress("0x00000000000000000000000000000000000000c0")
+	caller := &recordedCaller{t: t, responses: map[common.Address]map[string][]interface{}{
+		poolA: poolResponses(sqrtRatioX96, int64(tick)),
+		poolB: poolResponses(utils.EncodeSqrtRatioX96(big.NewInt(4), big.NewInt(1)), 13863),
+	}}
+	multicall := common.HexToAddress("0x00000000000000000000000000000000000000ff")
+
+	blockNumber, states, err := ReadPoolStates(context.Background(), caller, multicall, []common.Address{poolA, broken, poolB}, &PoolReadOptions{BatchSize: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(1234), blockNumber)
+	// the second batch is pinned to the block of the first one
+	assert.Equal(t, []*big.Int{nil, big.NewInt(1234)}, caller.blockNumbers)
+	assert.Equal(t, 3, len(states))
+
+	assert.NoError(t, states[0].Err)
+	assert.Equal(t, &PoolState{
+		Address:            poolA,
+		Token0:             token0.Address,
+		Token1:             token1.Address,
+		Fee:                feeAmount,
+		TickDistance:       constants.TickSpacings[feeAmount],
+		SqrtP:              sqrtRatioX96,
+		CurrentTick:        tick,
+		NearestCurrentTick: utils.MinTick,
+		BaseL:              big.NewInt(1_000_000),
+		ReinvestL:          big.NewInt(10),
+		ReinvestLLast:      big.NewInt(9),
+		FeeGrowthGlobal:    big.NewInt(42),
+	}, states[0])
+	assert.ErrorIs(t, states[1].Err, ErrPoolCallFailed)
+	assert.Equal(t, 13863, states[2].CurrentTick)
+
+	pool, err := states[0].NewPool(token0, token1, p)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, pool.SqrtP.Cmp(sqrtRatioX96))
+	assert.Equal(t, big.NewInt(1_000_000), pool.BaseL)
+	assert.Equal(t, big.NewInt(10), pool.ReinvestL)
+
+	_, err = states[0].NewPool(token0, token2, p)
+	assert.ErrorIs(t, err, ErrPoolTokensMismatch)
+	_, err = states[1].NewPool(token0, token1, p)
+	assert.ErrorIs(t, err, ErrPoolCallFailed)
+
+	params, err := PoolStateCallParameters([]common.Address{poolA}, nil)
+	assert.NoError(t, err)
+	_, _, err = DecodePoolStates([]common.Address{poolA, poolB}, mustCall(t, caller, params.Calldata))
+	assert.ErrorIs(t, err, ErrPoolResultsMismatch)
+}
+
+func mustCall(t *testing.T, caller ethereum.ContractCaller, calldata []byte) []byte {
+	data, err := caller.CallContract(context.Background(), ethereum.CallMsg{Data: calldata}, nil)
+	assert.NoError(t, err)
+	return data
+}
@@ -0,0 +1,110 @@
+package periphery
+
+import (
+	_ "embed"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+//go:embed contracts/elastic/interfaces/IBasePositionManager.sol/IBasePositionManager.json
+var basePositionManagerABI []byte
+
+var (
+	ErrPositionPoolMismatch = errors.New("position does not belong to the pool")
+)
+
+// The position of an Elastic position manager NFT, as returned by positions(tokenId)
+type PositionInfo struct {
+	Nonce               *big.Int
+	Operator            common.Address
+	PoolId              *big.Int
+	TickLower           int
+	TickUpper           int
+	Liquidity           *big.Int
+	RTokenOwed          *big.Int
+	FeeGrowthInsideLast *big.Int
+	Token0              common.Address
+	Fee                 constants.FeeAmount
+	Token1              common.Address
+}
+
+// PositionsCallParameters produces the calldata to read a position from the Elastic position manager
+func PositionsCallParameters(tokenID *big.Int) (*utils.MethodParameters, error) {
+	calldata, err := GetABI(basePositionManagerABI).Pack("positions", tokenID)
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{
+		Calldata: calldata,
+		Value:    big.NewInt(0),
+	}, nil
+}
+
+// DecodePositionInfo decodes the return data of positions(tokenId)
+func DecodePositionInfo(data []byte) (*PositionInfo, error) {
+	values, err := GetABI(basePositionManagerABI).Unpack("positions", data)
+	if err != nil {
+		return nil, err
+	}
+	var pos struct {
+		Nonce               *big.Int
+		Operator            common.Address
+		PoolId              *big.Int
+		TickLower           *big.Int
+		TickUpper           *big.Int
+		Liquidity           *big.Int
+		RTokenOwed          *big.Int
+		FeeGrowthInsideLast *big.Int
+	}
+	var info struct {
+		Token0 common.Address
+		Fee    *big.Int
+		Token1 common.Address
+	}
+	abi.ConvertType(values[0], &pos)
+	abi.ConvertType(values[1], &info)
+	return &PositionInfo{
+		Nonce:               pos.Nonce,
+		Operator:            pos.Operator,
+		PoolId:              pos.PoolId,
+		TickLower:           int(pos.TickLower.Int64()),
+		TickUpper:           int(pos.TickUpper.Int64()),
+		Liquidity:           pos.Liquidity,
+		RTokenOwed:          pos.RTokenOwed,
+		FeeGrowthInsideLast: pos.FeeGrowthInsideLast,
+		Token0:              info.Token0,
+		Fee:                 constants.FeeAmount(info.Fee.Uint64()),
+		Token1:              info.Token1,
+	}, nil
+}
+
+/**
+ * Constructs the position entity in the given pool
+ * @param pool the pool of the position, whose tokens and fee must match the position's
+ */
+func (i *PositionInfo) Position(pool *entities.Pool) (*entities.Position, error) {
+	if pool.Token0.Address != i.Token0 || pool.Token1.Address != i.Token1 || pool.Fee != i.Fee {
+		return nil, ErrPositionPoolMismatch
+	}
+	return entities.NewPosition(pool, i.Liquidity, i.TickLower, i.TickUpper)
+}
+
+/**
+ * Decodes the return data of positions(tokenId) into a position in the given pool
+ * @param data the return data of the call
+ * @param pool the pool of the position, whose tokens and fee must match the position's
+ */
+func DecodePosition(data []byte, pool *entities.Pool) (*entities.Position, error) {
+	info, err := DecodePositionInfo(data)
+	if err != nil {
+		return nil, err
+	}
+	return info.Position(pool)
+}
@@ -0,0 +1,81 @@
+package periphery
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+)
+
+type positionOutput struct {
+	Nonce               *big.Int
+	Operator            common.Address
+	PoolId              *big.Int
+	TickLower           *big.Int
+	TickUpper           *big.Int
+	Liquidity           *big.Int
+	RTokenOwed          *big.Int
+	FeeGrowthInsideLast *big.Int
+}
+
+type poolInfoOutput struct {
+	Token0 common.Address
+	Fee    *big.Int
+	Token1 common.Address
+}
+
+func TestDecodePosition(t *testing.T) {
+	params, err := PositionsCallParameters(big.NewInt(7))
+	assert.NoError(t, err)
+	values, err := GetABI(basePositionManagerABI).Methods["positions"].Inputs.Unpack(params.Calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(7)}, values)
+
+	spacing := constants.TickSpacings[feeAmount]
+	data, err := GetABI(basePositionManagerABI).Methods["positions"].Outputs.Pack(
+		positionOutput{
+			Nonce:               big.NewInt(1),
+			Operator:            recipientT,
+			PoolId:              big.NewInt(3),
+			TickLower:           big.NewInt(int64(-spacing)),
+			TickUpper:           big.NewInt(int64(spacing)),
+			Liquidity:           big.NewInt(1000),
+			RTokenOwed:          big.NewInt(5),
+			FeeGrowthInsideLast: big.NewInt(6),
+		},
+		poolInfoOutput{Token0: token0.Address, Fee: big.NewInt(int64(feeAmount)), Token1: token1.Address},
+	)
+	assert.NoError(t, err)
+
+	info, err := DecodePositionInfo(data)
+	assert.NoError(t, err)
+	assert.Equal(t, &PositionInfo{
+		Nonce:               big.NewInt(1),
+		Operator:            recipientT,
+		PoolId:              big.NewInt(3),
+		TickLower:           -spacing,
+		TickUpper:           spacing,
+		Liquidity:           big.NewInt(1000),
+		RTokenOwed:          big.NewInt(5),
+		FeeGrowthInsideLast: big.NewInt(6),
+		Token0:              token0.Address,
+		Fee:                 feeAmount,
+		Token1:              token1.Address,
+	}, info)
+
+	pool := makePool(token0, token1)
+	position, err := DecodePosition(data, pool)
+	assert.NoError(t, err)
+	assert.Equal(t, pool, position.Pool)
+	assert.Equal(t, big.NewInt(1000), position.Liquidity)
+	assert.Equal(t, -spacing, position.TickLower)
+	assert.Equal(t, spacing, position.TickUpper)
+
+	_, err = DecodePosition(data, makePool(token0, token2))
+	assert.ErrorIs(t, err, ErrPositionPoolMismatch)
+	_, err = DecodePosition(data, pool_1_2_low)
+	assert.ErrorIs(t, err, ErrPositionPoolMismatch)
+}
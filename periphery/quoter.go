@@ -3,9 +3,7 @@ package periphery
 import (
 	_ "embed"
 	"errors"
-	"fmt"
 	"math/big"
-	"reflect"
 
 	core "github.com/daoleno/uniswap-sdk-core/entities"
 	"github.com/ethereum/go-ethereum/common"
@@ -95,57 +93,34 @@ func QuoteCallParameters(
  * @param exactOutput whether the route should be encoded in reverse, for making exact output swaps
  */
 func EncodeRouteToPath(route *entities.Route, exactOutput bool) ([]byte, error) {
-	var (
-		inputToken = route.Input.Wrapped()
-
-		types []string
-		path  []interface{}
-
-		addressTy = "address"
-		uint24Ty  = "uint24"
-	)
-
-	for i, pool := range route.Pools {
-		var outputToken *core.Token
-		if pool.Token0.Equal(inputToken) {
-			outputToken = pool.Token1
-		} else {
-			outputToken = pool.Token0
-		}
-		if i == 0 {
-			types = []string{addressTy, uint24Ty, addressTy}
-			path = []interface{}{inputToken.Address, uint64(pool.Fee), outputToken.Address}
-		} else {
-			types = append(types, uint24Ty, addressTy)
-			path = append(path, uint64(pool.Fee), outputToken.Address)
-		}
-		inputToken = outputToken
-	}
+	return route.EncodePath(exactOutput)
+}
 
-	if exactOutput {
-		reverse(types)
-		reverse(path)
+/**
+ * Encodes a path of token addresses and fees as EncodeRouteToPath does, the tokens being resolved by their address
+ * through the registry
+ * @param registry the registry the tokens are resolved through
+ * @param chainID the chain of the tokens
+ * @param path the tokens and fees of the path, in the order of the swap
+ * @param exactOutput whether the path should be encoded in reverse, for making exact output swaps
+ */
+func EncodePathByAddress(registry *entities.TokenRegistry, chainID uint, path *Path, exactOutput bool) ([]byte, error) {
+	if len(path.Fees) == 0 || len(path.Tokens) != len(path.Fees)+1 {
+		return nil, ErrInvalidPath
 	}
-
-	// tight pack the path
-	var packedPath [][]byte
-	for i, t := range types {
-		switch t {
-		case addressTy:
-			packedPath = append(packedPath, path[i].(common.Address).Bytes())
-		case uint24Ty:
-			packedPath = append(packedPath, common.LeftPadBytes(PutUint24(path[i].(uint64)), 24/8))
-		default:
-			return nil, fmt.Errorf("unknown type %s", t)
+	tokens := make([]common.Address, len(path.Tokens))
+	for i, address := range path.Tokens {
+		token, err := registry.Token(chainID, address)
+		if err != nil {
+			return nil, err
 		}
-
+		tokens[i] = token.Address
 	}
-
-	var pack []byte
-	for _, p := range packedPath {
-		pack = append(pack, p...)
+	hops := make([][]byte, len(path.Fees))
+	for i, fee := range path.Fees {
+		hops[i] = entities.FeePathHop(fee)
 	}
-	return pack, nil
+	return entities.EncodePath(tokens, hops, exactOutput)
 }
 
 // PutUint24 put bigendian uint24
@@ -156,11 +131,3 @@ func PutUint24(i uint64) []byte {
 	b[2] = byte(i)
 	return b
 }
-
-func reverse(s interface{}) {
-	n := reflect.ValueOf(s).Len()
-	swap := reflect.Swapper(s)
-	for i, j := 0, n-1; i < j; i, j = i+1, j-1 {
-		swap(i, j)
-	}
-}
@@ -0,0 +1,297 @@
+package periphery
+
+import (
+	_ "embed"
+	"errors"
+	"math/big"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+//go:embed contracts/lens/QuoterV2.sol/QuoterV2.json
+var quoterV2ABI []byte
+
+//go:embed contracts/elastic/lens/QuoterV2.sol/QuoterV2.json
+var elasticQuoterV2ABI []byte
+
+var (
+	ErrQuoteHopsMismatch = errors.New("quote result hops do not match the route")
+	ErrMultipleSwaps     = errors.New("trade must consist of a single swap")
+)
+
+// QuoterVersion selects the quoter contract calldata is built for and results are decoded from
+type QuoterVersion int
+
+const (
+	QuoterV1        QuoterVersion = iota // The Uniswap V3 Quoter, which only returns the quoted amount
+	QuoterV2                             // The Uniswap V3 QuoterV2
+	ElasticQuoterV2                      // The KyberSwap Elastic QuoterV2
+)
+
+type QuoteExactInputSingleParams struct {
+	TokenIn           common.Address
+	TokenOut          common.Address
+	AmountIn          *big.Int
+	Fee               *big.Int
+	SqrtPriceLimitX96 *big.Int
+}
+
+type QuoteExactOutputSingleParams struct {
+	TokenIn           common.Address
+	TokenOut          common.Address
+	Amount            *big.Int
+	Fee               *big.Int
+	SqrtPriceLimitX96 *big.Int
+}
+
+type ElasticQuoteExactInputSingleParams struct {
+	TokenIn    common.Address
+	TokenOut   common.Address
+	AmountIn   *big.Int
+	FeeUnits   *big.Int
+	LimitSqrtP *big.Int
+}
+
+type ElasticQuoteExactOutputSingleParams struct {
+	TokenIn    common.Address
+	TokenOut   common.Address
+	Amount     *big.Int
+	FeeUnits   *big.Int
+	LimitSqrtP *big.Int
+}
+
+// QuoterV2Result is the decoded result of a QuoterV2 call, single hop results are reported as one hop lists
+type QuoterV2Result struct {
+	Amount                      *big.Int   // The quoted amount, the output for exact input quotes and the input for exact output quotes
+	AmountUsed                  *big.Int   // The part of the specified amount that was swapped, only reported by single hop Elastic quotes
+	SqrtPriceX96AfterList       []*big.Int // The price of each pool after the swap, in route order for exact input and reversed for exact output
+	InitializedTicksCrossedList []uint32   // The number of initialized ticks crossed in each pool, in the same order
+	GasEstimate                 *big.Int   // The gas estimate of the swap
+}
+
+// InitializedTicksCrossed returns the number of initialized ticks crossed over all hops
+func (r *QuoterV2Result) InitializedTicksCrossed() int {
+	var crossed int
+	for _, c := range r.InitializedTicksCrossedList {
+		crossed += int(c)
+	}
+	return crossed
+}
+
+// elasticQuoteOutput is the struct returned by the single hop quotes of the Elastic QuoterV2
+type elasticQuoteOutput struct {
+	UsedAmount              *big.Int
+	ReturnedAmount          *big.Int
+	AfterSqrtP              *big.Int
+	InitializedTicksCrossed uint32
+	GasEstimate             *big.Int
+}
+
+func (v QuoterVersion) abi() abi.ABI {
+	switch v {
+	case QuoterV2:
+		return GetABI(quoterV2ABI)
+	case ElasticQuoterV2:
+		return GetABI(elasticQuoterV2ABI)
+	default:
+		return GetABI(quoterABI)
+	}
+}
+
+func quoteMethod(route *entities.Route, tradeType core.TradeType) string {
+	method := "quoteExactInput"
+	if tradeType == core.ExactOutput {
+		method = "quoteExactOutput"
+	}
+	if len(route.Pools) == 1 {
+		method += "Single"
+	}
+	return method
+}
+
+/**
+ * Produces the calldata of the appropriate quote function of the given quoter version.
+ * @param version The quoter contract to call
+ * @param route The swap route, a list of pools through which a swap can occur
+ * @param amount The amount of the quote, either an amount in, or an amount out
+ * @param tradeType The trade type, either exact input or exact output
+ * @param options The optional price limit, only supported for single hop routes
+ * @returns The formatted calldata
+ */
+func QuoteCallParametersV2(version QuoterVersion, route *entities.Route, amount *core.CurrencyAmount, tradeType core.TradeType, options *QuoteOptions) (*utils.MethodParameters, error) {
+	if version == QuoterV1 {
+		return QuoteCallParameters(route, amount, tradeType, options)
+	}
+
+	quoteAmount := amount.Quotient()
+	sqrtPriceLimitX96 := big.NewInt(0)
+	if options != nil && options.SqrtPriceLimitX96 != nil {
+		sqrtPriceLimitX96 = options.SqrtPriceLimitX96
+	}
+	method := quoteMethod(route, tradeType)
+	abi := version.abi()
+
+	var args []interface{}
+	if len(route.Pools) == 1 {
+		tokenIn, tokenOut := route.TokenPath[0].Address, route.TokenPath[1].Address
+		fee := big.NewInt(int64(route.Pools[0].Fee))
+		switch {
+		case version == QuoterV2 && tradeType == core.ExactInput:
+			args = []interface{}{QuoteExactInputSingleParams{tokenIn, tokenOut, quoteAmount, fee, sqrtPriceLimitX96}}
+		case version == QuoterV2:
+			args = []interface{}{QuoteExactOutputSingleParams{tokenIn, tokenOut, quoteAmount, fee, sqrtPriceLimitX96}}
+		case tradeType == core.ExactInput:
+			args = []interface{}{ElasticQuoteExactInputSingleParams{tokenIn, tokenOut, quoteAmount, fee, sqrtPriceLimitX96}}
+		default:
+			args = []interface{}{ElasticQuoteExactOutputSingleParams{tokenIn, tokenOut, quoteAmount, fee, sqrtPriceLimitX96}}
+		}
+	} else {
+		if sqrtPriceLimitX96.Sign() != 0 {
+			return nil, ErrMultihopPriceLimit
+		}
+		path, err := EncodeRouteToPath(route, tradeType == core.ExactOutput)
+		if err != nil {
+			return nil, err
+		}
+		args = []interface{}{path, quoteAmount}
+	}
+
+	calldata, err := abi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{
+		Calldata: calldata,
+		Value:    big.NewInt(0),
+	}, nil
+}
+
+/**
+ * Decodes the return data of a V1 quoter call built by QuoteCallParameters
+ * @param route The route that was quoted
+ * @param tradeType The trade type that was quoted
+ * @param data The return data of the call
+ * @returns The quoted amount, the output for exact input quotes and the input for exact output quotes
+ */
+func DecodeQuoteResult(route *entities.Route, tradeType core.TradeType, data []byte) (*big.Int, error) {
+	values, err := QuoterV1.abi().Unpack(quoteMethod(route, tradeType), data)
+	if err != nil {
+		return nil, err
+	}
+	return values[0].(*big.Int), nil
+}
+
+/**
+ * Decodes the return data of a QuoterV2 call built by QuoteCallParametersV2
+ * @param version The quoter contract that was called
+ * @param route The route that was quoted
+ * @param tradeType The trade type that was quoted
+ * @param data The return data of the call
+ * @returns The typed result, only the amount is set for QuoterV1
+ */
+func DecodeQuoteResultV2(version QuoterVersion, route *entities.Route, tradeType core.TradeType, data []byte) (*QuoterV2Result, error) {
+	if version == QuoterV1 {
+		amount, err := DecodeQuoteResult(route, tradeType, data)
+		if err != nil {
+			return nil, err
+		}
+		return &QuoterV2Result{Amount: amount}, nil
+	}
+	method := version.abi().Methods[quoteMethod(route, tradeType)]
+	values, err := method.Outputs.Unpack(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var result QuoterV2Result
+	switch {
+	case len(route.Pools) > 1:
+		// the multi hop outputs of both versions only differ by name
+		result = QuoterV2Result{
+			Amount:                      values[0].(*big.Int),
+			SqrtPriceX96AfterList:       values[1].([]*big.Int),
+			InitializedTicksCrossedList: values[2].([]uint32),
+			GasEstimate:                 values[3].(*big.Int),
+		}
+		if len(result.SqrtPriceX96AfterList) != len(route.Pools) || len(result.InitializedTicksCrossedList) != len(route.Pools) {
+			return nil, ErrQuoteHopsMismatch
+		}
+	case version == ElasticQuoterV2:
+		out := abi.ConvertType(values[0], new(elasticQuoteOutput)).(*elasticQuoteOutput)
+		// the used amount is always the input and the returned amount the output, whichever of them was specified
+		amount, used := out.ReturnedAmount, out.UsedAmount
+		if tradeType == core.ExactOutput {
+			amount, used = out.UsedAmount, out.ReturnedAmount
+		}
+		result = QuoterV2Result{
+			Amount:                      amount,
+			AmountUsed:                  used,
+			SqrtPriceX96AfterList:       []*big.Int{out.AfterSqrtP},
+			InitializedTicksCrossedList: []uint32{out.InitializedTicksCrossed},
+			GasEstimate:                 out.GasEstimate,
+		}
+	default:
+		result = QuoterV2Result{
+			Amount:                      values[0].(*big.Int),
+			SqrtPriceX96AfterList:       []*big.Int{values[1].(*big.Int)},
+			InitializedTicksCrossedList: []uint32{values[2].(uint32)},
+			GasEstimate:                 values[3].(*big.Int),
+		}
+	}
+	return &result, nil
+}
+
+// QuoteComparison compares an on-chain quote with the local simulation of the same swap
+type QuoteComparison struct {
+	Local               *big.Int // The amount computed by the SDK
+	OnChain             *big.Int // The amount returned by the quoter
+	Difference          *big.Int // OnChain - Local
+	LocalTicksCrossed   int      // The initialized ticks crossed by the SDK simulation
+	OnChainTicksCrossed int      // The initialized ticks crossed reported by the quoter
+}
+
+// Matches returns whether the on-chain quote and the local simulation agree exactly on the amount
+func (c *QuoteComparison) Matches() bool {
+	return c.Difference.Sign() == 0
+}
+
+// WithinTolerance returns whether the amounts differ by at most the given fraction of the local amount
+func (c *QuoteComparison) WithinTolerance(tolerance *core.Percent) bool {
+	if c.Local.Sign() == 0 {
+		return c.Matches()
+	}
+	diff := core.NewFraction(new(big.Int).Abs(c.Difference), c.Local)
+	return !tolerance.LessThan(diff)
+}
+
+/**
+ * Compares an on-chain quote with the SDK's simulation of the same swap
+ * @param trade The local trade, which must consist of a single swap through the quoted route
+ * @param result The decoded quoter result, the ticks crossed are only compared for QuoterV2 results
+ * @returns The comparison of the quoted amount and ticks crossed
+ */
+func CompareQuote(trade *entities.Trade, result *QuoterV2Result) (*QuoteComparison, error) {
+	if len(trade.Swaps) != 1 {
+		return nil, ErrMultipleSwaps
+	}
+	swap := trade.Swaps[0]
+	if result.SqrtPriceX96AfterList != nil && len(result.SqrtPriceX96AfterList) != len(swap.Route.Pools) {
+		return nil, ErrQuoteHopsMismatch
+	}
+	local := swap.OutputAmount.Quotient()
+	if trade.TradeType == core.ExactOutput {
+		local = swap.InputAmount.Quotient()
+	}
+	return &QuoteComparison{
+		Local:               local,
+		OnChain:             result.Amount,
+		Difference:          new(big.Int).Sub(result.Amount, local),
+		LocalTicksCrossed:   swap.TicksCrossed,
+		OnChainTicksCrossed: result.InitializedTicksCrossed(),
+	}, nil
+}
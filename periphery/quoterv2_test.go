@@ -0,0 +1,157 @@
+package periphery
+
+import (
+	"math/big"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+)
+
+func TestQuoteCallParametersV2(t *testing.T) {
+	amount := core.FromRawAmount(token0, big.NewInt(100))
+
+	params, err := QuoteCallParametersV2(QuoterV2, route_0_1, amount, core.ExactInput, nil)
+	assert.NoError(t, err)
+	values, err := GetABI(quoterV2ABI).Methods["quoteExactInputSingle"].Inputs.Unpack(params.Calldata[4:])
+	assert.NoError(t, err)
+	single := abi.ConvertType(values[0], new(QuoteExactInputSingleParams)).(*QuoteExactInputSingleParams)
+	assert.Equal(t, token0.Address, single.TokenIn)
+	assert.Equal(t, token1.Address, single.TokenOut)
+	assert.Equal(t, big.NewInt(100), single.AmountIn)
+	assert.Equal(t, big.NewInt(int64(feeAmount)), single.Fee)
+	assert.Equal(t, 0, single.SqrtPriceLimitX96.Sign())
+
+	params, err = QuoteCallParametersV2(ElasticQuoterV2, route_0_1, core.FromRawAmount(token1, big.NewInt(100)), core.ExactOutput, &QuoteOptions{SqrtPriceLimitX96: big.NewInt(5)})
+	assert.NoError(t, err)
+	values, err = GetABI(elasticQuoterV2ABI).Methods["quoteExactOutputSingle"].Inputs.Unpack(params.Calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, &ElasticQuoteExactOutputSingleParams{
+		TokenIn:    token0.Address,
+		TokenOut:   token1.Address,
+		Amount:     big.NewInt(100),
+		FeeUnits:   big.NewInt(int64(feeAmount)),
+		LimitSqrtP: big.NewInt(5),
+	}, abi.ConvertType(values[0], new(ElasticQuoteExactOutputSingleParams)))
+
+	params, err = QuoteCallParametersV2(ElasticQuoterV2, route_0_1_2, amount, core.ExactInput, nil)
+	assert.NoError(t, err)
+	path, _ := EncodeRouteToPath(route_0_1_2, false)
+	expected, _ := GetABI(elasticQuoterV2ABI).Pack("quoteExactInput", path, big.NewInt(100))
+	assert.Equal(t, expected, params.Calldata)
+
+	_, err = QuoteCallParametersV2(QuoterV2, route_0_1_2, amount, core.ExactInput, &QuoteOptions{SqrtPriceLimitX96: big.NewInt(5)})
+	assert.ErrorIs(t, err, ErrMultihopPriceLimit)
+
+	v1, err := QuoteCallParametersV2(QuoterV1, route_0_1, amount, core.ExactInput, nil)
+	assert.NoError(t, err)
+	expectedV1, _ := QuoteCallParameters(route_0_1, amount, core.ExactInput, nil)
+	assert.Equal(t, expectedV1, v1)
+}
+
+func TestDecodeQuoteResultV2(t *testing.T) {
+	// multi hop
+	data, err := GetABI(quoterV2ABI).Methods["quoteExactInput"].Outputs.Pack(big.NewInt(98), []*big.Int{big.NewInt(1), big.NewInt(2)}, []uint32{3, 0}, big.NewInt(150000))
+	assert.NoError(t, err)
+	result, err := DecodeQuoteResultV2(QuoterV2, route_0_1_2, core.ExactInput, data)
+	assert.NoError(t, err)
+	assert.Equal(t, &QuoterV2Result{
+		Amount:                      big.NewInt(98),
+		SqrtPriceX96AfterList:       []*big.Int{big.NewInt(1), big.NewInt(2)},
+		InitializedTicksCrossedList: []uint32{3, 0},
+		GasEstimate:                 big.NewInt(150000),
+	}, result)
+	assert.Equal(t, 3, result.InitializedTicksCrossed())
+
+	_, err = DecodeQuoteResultV2(QuoterV2, &entities.Route{Pools: []*entities.Pool{pool_0_1_medium, pool_1_2_low, pool_1_2_low}}, core.ExactInput, data)
+	assert.ErrorIs(t, err, ErrQuoteHopsMismatch)
+
+	// uniswap single hop
+	data, err = GetABI(quoterV2ABI).Methods["quoteExactOutputSingle"].Outputs.Pack(big.NewInt(102), big.NewInt(7), uint32(1), big.NewInt(80000))
+	assert.NoError(t, err)
+	result, err = DecodeQuoteResultV2(QuoterV2, route_0_1, core.ExactOutput, data)
+	assert.NoError(t, err)
+	assert.Equal(t, &QuoterV2Result{
+		Amount:                      big.NewInt(102),
+		SqrtPriceX96AfterList:       []*big.Int{big.NewInt(7)},
+		InitializedTicksCrossedList: []uint32{1},
+		GasEstimate:                 big.NewInt(80000),
+	}, result)
+
+	// elastic single hop
+	data, err = GetABI(elasticQuoterV2ABI).Methods["quoteExactInputSingle"].Outputs.Pack(elasticQuoteOutput{
+		UsedAmount:              big.NewInt(100),
+		ReturnedAmount:          big.NewInt(97),
+		AfterSqrtP:              big.NewInt(7),
+		InitializedTicksCrossed: 2,
+		GasEstimate:             big.NewInt(90000),
+	})
+	assert.NoError(t, err)
+	result, err = DecodeQuoteResultV2(ElasticQuoterV2, route_0_1, core.ExactInput, data)
+	assert.NoError(t, err)
+	assert.Equal(t, &QuoterV2Result{
+		Amount:                      big.NewInt(97),
+		AmountUsed:                  big.NewInt(100),
+		SqrtPriceX96AfterList:       []*big.Int{big.NewInt(7)},
+		InitializedTicksCrossedList: []uint32{2},
+		GasEstimate:                 big.NewInt(90000),
+	}, result)
+
+	// elastic single hop exact output, the quoted input is the used amount
+	data, err = GetABI(elasticQuoterV2ABI).Methods["quoteExactOutputSingle"].Outputs.Pack(elasticQuoteOutput{
+		UsedAmount:              big.NewInt(103),
+		ReturnedAmount:          big.NewInt(100),
+		AfterSqrtP:              big.NewInt(7),
+		InitializedTicksCrossed: 2,
+		GasEstimate:             big.NewInt(90000),
+	})
+	assert.NoError(t, err)
+	result, err = DecodeQuoteResultV2(ElasticQuoterV2, route_0_1, core.ExactOutput, data)
+	assert.NoError(t, err)
+	assert.Equal(t, &QuoterV2Result{
+		Amount:                      big.NewInt(103),
+		AmountUsed:                  big.NewInt(100),
+		SqrtPriceX96AfterList:       []*big.Int{big.NewInt(7)},
+		InitializedTicksCrossedList: []uint32{2},
+		GasEstimate:                 big.NewInt(90000),
+	}, result)
+
+	// v1
+	data, err = GetABI(quoterABI).Methods["quoteExactInput"].Outputs.Pack(big.NewInt(98))
+	assert.NoError(t, err)
+	amount, err := DecodeQuoteResult(route_0_1_2, core.ExactInput, data)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(98), amount)
+}
+
+func TestCompareQuote(t *testing.T) {
+	route, err := entities.NewRoute([]*entities.Pool{makePool(token0, token1), makePool(token1, token2)}, token0, token2)
+	assert.NoError(t, err)
+	trade, err := entities.FromRoute(route, core.FromRawAmount(token0, big.NewInt(1000)), core.ExactInput)
+	assert.NoError(t, err)
+	local := trade.OutputAmount().Quotient()
+
+	onChain := &QuoterV2Result{
+		Amount:                      new(big.Int).Add(local, big.NewInt(1)),
+		SqrtPriceX96AfterList:       []*big.Int{big.NewInt(1), big.NewInt(2)},
+		InitializedTicksCrossedList: []uint32{0, 0},
+		GasEstimate:                 big.NewInt(0),
+	}
+	comparison, err := CompareQuote(trade, onChain)
+	assert.NoError(t, err)
+	assert.Equal(t, local, comparison.Local)
+	assert.Equal(t, big.NewInt(1), comparison.Difference)
+	assert.False(t, comparison.Matches())
+	assert.True(t, comparison.WithinTolerance(core.NewPercent(big.NewInt(1), big.NewInt(100))))
+	assert.False(t, comparison.WithinTolerance(core.NewPercent(big.NewInt(0), big.NewInt(100))))
+
+	comparison, err = CompareQuote(trade, &QuoterV2Result{Amount: local})
+	assert.NoError(t, err)
+	assert.True(t, comparison.Matches())
+
+	_, err = CompareQuote(trade, &QuoterV2Result{Amount: local, SqrtPriceX96AfterList: []*big.Int{big.NewInt(1)}})
+	assert.ErrorIs(t, err, ErrQuoteHopsMismatch)
+}
@@ -25,12 +25,13 @@ var (
 
 // Options for producing the arguments to send calls to the router.
 type SwapOptions struct {
-	SlippageTolerance *core.Percent  // How much the execution price is allowed to move unfavorably from the trade execution price.
-	Recipient         common.Address // The account that should receive the output.
-	Deadline          *big.Int       // When the transaction expires, in epoch seconds.
-	InputTokenPermit  *PermitOptions // The optional permit parameters for spending the input.
-	SqrtPriceLimitX96 *big.Int       // The optional price limit for the trade.
-	Fee               *FeeOptions    // Optional information for taking a fee on output.
+	SlippageTolerance *core.Percent   // How much the execution price is allowed to move unfavorably from the trade execution price.
+	Recipient         common.Address  // The account that should receive the output.
+	Deadline          *big.Int        // When the transaction expires, in epoch seconds.
+	InputTokenPermit  *PermitOptions  // The optional permit parameters for spending the input.
+	InputTokenPermit2 *Permit2Options // The optional Permit2 permit for spending the input, sent to Permit2 before the swap.
+	SqrtPriceLimitX96 *big.Int        // The optional price limit for the trade.
+	Fee               *FeeOptions     // Optional information for taking a fee on output.
 }
 
 type ExactInputSingleParams struct {
@@ -138,6 +139,18 @@ func SwapCallParameters(trades []*entities.Trade, options *SwapOptions) (*utils.
 		}
 		calldatas = append(calldatas, permit)
 	}
+	var preCalls []*utils.ContractCall
+	if options.InputTokenPermit2 != nil {
+		if !sampleTrade.InputAmount().Currency.IsToken() {
+			return nil, ErrNonTokenPermit
+		}
+
+		preCall, err := Permit2PreCall(tokenIn.Address, options.InputTokenPermit2.Spender, options.InputTokenPermit2.Permit2PermitArguments)
+		if err != nil {
+			return nil, err
+		}
+		preCalls = append(preCalls, preCall)
+	}
 
 	recipient := options.Recipient
 	if routerMustCustody {
@@ -275,5 +288,6 @@ func SwapCallParameters(trades []*entities.Trade, options *SwapOptions) (*utils.
 	return &utils.MethodParameters{
 		Calldata: call,
 		Value:    totalValue.Quotient(),
+		PreCalls: preCalls,
 	}, nil
 }
@@ -0,0 +1,200 @@
+package periphery
+
+import (
+	_ "embed"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+//go:embed contracts/lens/TickLens.sol/TickLens.json
+var tickLensABI []byte
+
+//go:embed contracts/elastic/TicksFeesReader.sol/TicksFeesReader.json
+var ticksFeesReaderABI []byte
+
+//go:embed contracts/elastic/interfaces/pool/IPoolStorage.sol/IPoolStorage.json
+var poolStorageABI []byte
+
+var (
+	ErrTickResultsMismatch = errors.New("number of tick results does not match the number of ticks")
+	ErrDuplicateTick       = errors.New("duplicate tick")
+)
+
+// TickBitmapIndex returns the index of the tick bitmap word containing the given tick, as used by getPopulatedTicksInWord
+func TickBitmapIndex(tick, tickSpacing int) int16 {
+	compressed := tick / tickSpacing
+	if tick < 0 && tick%tickSpacing != 0 {
+		// round towards negative infinity
+		compressed--
+	}
+	return int16(compressed >> 8)
+}
+
+/**
+ * Produces the calldata to read the populated ticks of one tick bitmap word of a pool from the Uniswap TickLens
+ * @param pool the address of the pool
+ * @param tickBitmapIndex the index of the word, see TickBitmapIndex
+ */
+func PopulatedTicksInWordCallParameters(pool common.Address, tickBitmapIndex int16) (*utils.MethodParameters, error) {
+	calldata, err := GetABI(tickLensABI).Pack("getPopulatedTicksInWord", pool, tickBitmapIndex)
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{
+		Calldata: calldata,
+		Value:    big.NewInt(0),
+	}, nil
+}
+
+/**
+ * Decodes the return data of getPopulatedTicksInWord calls into a tick list
+ * @param tickSpacing the tick spacing of the pool
+ * @param words the return data of one call per tick bitmap word, which must together cover every initialized tick
+ * @returns The ticks of all words, sorted and validated
+ */
+func DecodePopulatedTicks(tickSpacing int, words ...[]byte) ([]entities.Tick, error) {
+	abi := GetABI(tickLensABI)
+	var ticks []entities.Tick
+	for _, data := range words {
+		values, err := abi.Unpack("getPopulatedTicksInWord", data)
+		if err != nil {
+			return nil, err
+		}
+		var populated []struct {
+			Tick           *big.Int
+			LiquidityNet   *big.Int
+			LiquidityGross *big.Int
+		}
+		if err := abi.Methods["getPopulatedTicksInWord"].Outputs.Copy(&populated, values); err != nil {
+			return nil, err
+		}
+		for _, t := range populated {
+			ticks = append(ticks, entities.Tick{
+				Index:          int(t.Tick.Int64()),
+				LiquidityGross: t.LiquidityGross,
+				LiquidityNet:   t.LiquidityNet,
+			})
+		}
+	}
+	return sortTicks(ticks, tickSpacing)
+}
+
+/**
+ * Produces the calldata to read the initialized ticks of an Elastic pool from the TicksFeesReader
+ * @param pool the address of the pool
+ * @param startTick the initialized tick to start from
+ * @param length the maximum number of ticks to return, 0 to return every tick up to the maximum tick
+ */
+func TicksInRangeCallParameters(pool common.Address, startTick int, length uint32) (*utils.MethodParameters, error) {
+	calldata, err := GetABI(ticksFeesReaderABI).Pack("getTicksInRange", pool, big.NewInt(int64(startTick)), length)
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{
+		Calldata: calldata,
+		Value:    big.NewInt(0),
+	}, nil
+}
+
+// DecodeTicksInRange decodes the return data of getTicksInRange into tick indexes, in the order returned
+func DecodeTicksInRange(data []byte) ([]int, error) {
+	values, err := GetABI(ticksFeesReaderABI).Unpack("getTicksInRange", data)
+	if err != nil {
+		return nil, err
+	}
+	var ticks []int
+	for _, t := range values[0].([]*big.Int) {
+		ticks = append(ticks, int(t.Int64()))
+	}
+	return ticks, nil
+}
+
+/**
+ * Produces the calldata to read the initialized ticks surrounding a tick of an Elastic pool from the TicksFeesReader
+ * @param pool the address of the pool
+ * @param tick the tick to search from
+ */
+func NearestInitializedTicksCallParameters(pool common.Address, tick int) (*utils.MethodParameters, error) {
+	calldata, err := GetABI(ticksFeesReaderABI).Pack("getNearestInitializedTicks", pool, big.NewInt(int64(tick)))
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{
+		Calldata: calldata,
+		Value:    big.NewInt(0),
+	}, nil
+}
+
+// DecodeNearestInitializedTicks decodes the return data of getNearestInitializedTicks
+func DecodeNearestInitializedTicks(data []byte) (previous int, next int, err error) {
+	values, err := GetABI(ticksFeesReaderABI).Unpack("getNearestInitializedTicks", data)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(values[0].(*big.Int).Int64()), int(values[1].(*big.Int).Int64()), nil
+}
+
+// ElasticTickCallParameters produces the calldata to read the liquidity of a tick from an Elastic pool
+func ElasticTickCallParameters(tick int) (*utils.MethodParameters, error) {
+	calldata, err := GetABI(poolStorageABI).Pack("ticks", big.NewInt(int64(tick)))
+	if err != nil {
+		return nil, err
+	}
+	return &utils.MethodParameters{
+		Calldata: calldata,
+		Value:    big.NewInt(0),
+	}, nil
+}
+
+/**
+ * Decodes the return data of the ticks calls of an Elastic pool into a tick list. Ticks without liquidity, such as the
+ * minimum and maximum tick sentinels of the pool's linked list, are dropped.
+ * @param tickSpacing the tick spacing of the pool
+ * @param indexes the ticks that were read, e.g. as returned by DecodeTicksInRange
+ * @param results the return data of the ticks call of each index
+ * @returns The ticks, sorted and validated
+ */
+func DecodeElasticTicks(tickSpacing int, indexes []int, results [][]byte) ([]entities.Tick, error) {
+	if len(indexes) != len(results) {
+		return nil, ErrTickResultsMismatch
+	}
+	abi := GetABI(poolStorageABI)
+	var ticks []entities.Tick
+	for i, data := range results {
+		values, err := abi.Unpack("ticks", data)
+		if err != nil {
+			return nil, err
+		}
+		liquidityGross, liquidityNet := values[0].(*big.Int), values[1].(*big.Int)
+		if liquidityGross.Sign() == 0 {
+			continue
+		}
+		ticks = append(ticks, entities.Tick{
+			Index:          indexes[i],
+			LiquidityGross: liquidityGross,
+			LiquidityNet:   liquidityNet,
+		})
+	}
+	return sortTicks(ticks, tickSpacing)
+}
+
+func sortTicks(ticks []entities.Tick, tickSpacing int) ([]entities.Tick, error) {
+	sort.Slice(ticks, func(i, j int) bool {
+		return ticks[i].Index < ticks[j].Index
+	})
+	for i := 1; i < len(ticks); i++ {
+		if ticks[i].Index == ticks[i-1].Index {
+			return nil, ErrDuplicateTick
+		}
+	}
+	if err := entities.ValidateList(ticks, tickSpacing); err != nil {
+		return nil, err
+	}
+	return ticks, nil
+}
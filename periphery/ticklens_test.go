@@ -0,0 +1,111 @@
+package periphery
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+type populatedTick struct {
+	Tick           *big.Int
+	LiquidityNet   *big.Int
+	LiquidityGross *big.Int
+}
+
+func TestTickBitmapIndex(t *testing.T) {
+	assert.Equal(t, int16(0), TickBitmapIndex(0, 1))
+	assert.Equal(t, int16(0), TickBitmapIndex(255*8, 8))
+	assert.Equal(t, int16(1), TickBitmapIndex(256*8, 8))
+	assert.Equal(t, int16(-1), TickBitmapIndex(-1, 8))
+	assert.Equal(t, int16(-1), TickBitmapIndex(-256*8, 8))
+	assert.Equal(t, int16(-2), TickBitmapIndex(-256*8-1, 8))
+}
+
+func TestDecodePopulatedTicks(t *testing.T) {
+	pool := common.HexToAddress("0x0000000000000000000000000000000000000005")
+	params, err := PopulatedTicksInWordCallParameters(pool, -3)
+	assert.NoError(t, err)
+	values, err := GetABI(tickLensABI).Methods["getPopulatedTicksInWord"].Inputs.Unpack(params.Calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{pool, int16(-3)}, values)
+
+	outputs := GetABI(tickLensABI).Methods["getPopulatedTicksInWord"].Outputs
+	// the lens returns the ticks of a word from highest to lowest
+	upper, err := outputs.Pack([]populatedTick{
+		{big.NewInt(2048), big.NewInt(-5), big.NewInt(5)},
+		{big.NewInt(80), big.NewInt(-10), big.NewInt(10)},
+	})
+	assert.NoError(t, err)
+	lower, err := outputs.Pack([]populatedTick{
+		{big.NewInt(-80), big.NewInt(15), big.NewInt(15)},
+	})
+	assert.NoError(t, err)
+
+	ticks, err := DecodePopulatedTicks(8, upper, lower)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{-80, 80, 2048}, []int{ticks[0].Index, ticks[1].Index, ticks[2].Index})
+	assert.Equal(t, big.NewInt(15), ticks[0].LiquidityNet)
+	assert.Equal(t, big.NewInt(10), ticks[1].LiquidityGross)
+	_, err = entities.NewTickListDataProvider(ticks, 8)
+	assert.NoError(t, err)
+
+	// missing words leave the liquidity deltas unbalanced
+	_, err = DecodePopulatedTicks(8, upper)
+	assert.ErrorIs(t, err, entities.ErrZeroNet)
+	_, err = DecodePopulatedTicks(8, upper, upper, lower)
+	assert.ErrorIs(t, err, ErrDuplicateTick)
+}
+
+func TestDecodeElasticTicks(t *testing.T) {
+	pool := common.HexToAddress("0x0000000000000000000000000000000000000005")
+	params, err := TicksInRangeCallParameters(pool, utils.MinTick, 0)
+	assert.NoError(t, err)
+	values, err := GetABI(ticksFeesReaderABI).Methods["getTicksInRange"].Inputs.Unpack(params.Calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{pool, big.NewInt(utils.MinTick), uint32(0)}, values)
+
+	data, err := GetABI(ticksFeesReaderABI).Methods["getTicksInRange"].Outputs.Pack([]*big.Int{big.NewInt(utils.MinTick), big.NewInt(-80), big.NewInt(80), big.NewInt(utils.MaxTick)})
+	assert.NoError(t, err)
+	indexes, err := DecodeTicksInRange(data)
+	assert.NoError(t, err)
+	assert.Equal(t, []int{utils.MinTick, -80, 80, utils.MaxTick}, indexes)
+
+	params, err = ElasticTickCallParameters(-80)
+	assert.NoError(t, err)
+	values, err = GetABI(poolStorageABI).Methods["ticks"].Inputs.Unpack(params.Calldata[4:])
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{big.NewInt(-80)}, values)
+
+	outputs := GetABI(poolStorageABI).Methods["ticks"].Outputs
+	var results [][]byte
+	for _, liquidityNet := range []int64{0, 10, -10, 0} {
+		liquidityGross := new(big.Int).Abs(big.NewInt(liquidityNet))
+		data, err := outputs.Pack(liquidityGross, big.NewInt(liquidityNet), big.NewInt(0), big.NewInt(0))
+		assert.NoError(t, err)
+		results = append(results, data)
+	}
+	ticks, err := DecodeElasticTicks(8, indexes, results)
+	assert.NoError(t, err)
+	assert.Equal(t, []entities.Tick{
+		{Index: -80, LiquidityGross: big.NewInt(10), LiquidityNet: big.NewInt(10)},
+		{Index: 80, LiquidityGross: big.NewInt(10), LiquidityNet: big.NewInt(-10)},
+	}, ticks)
+
+	_, err = DecodeElasticTicks(8, indexes, results[1:])
+	assert.ErrorIs(t, err, ErrTickResultsMismatch)
+
+	params, err = NearestInitializedTicksCallParameters(pool, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, GetABI(ticksFeesReaderABI).Methods["getNearestInitializedTicks"].ID, params.Calldata[:4])
+	data, err = GetABI(ticksFeesReaderABI).Methods["getNearestInitializedTicks"].Outputs.Pack(big.NewInt(-80), big.NewInt(80))
+	assert.NoError(t, err)
+	previous, next, err := DecodeNearestInitializedTicks(data)
+	assert.NoError(t, err)
+	assert.Equal(t, -80, previous)
+	assert.Equal(t, 80, next)
+}
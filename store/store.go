@@ -0,0 +1,329 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+var (
+	ErrInvalidRetention = errors.New("retention must be at least one block")
+	ErrUnknownParent    = errors.New("the parent of the block is not in the store")
+	ErrBlockNotFound    = errors.New("block not found")
+	ErrBlockPruned      = errors.New("block is older than the blocks retained")
+	ErrUnknownPool      = errors.New("pool not tracked")
+	ErrInvalidSwap      = errors.New("a swap must pay exactly one token into the pool and take the other out")
+	ErrInvalidLiquidity = errors.New("the liquidity of a mint or burn must be positive")
+)
+
+// A block of the chain the pools are tracked on
+type Block struct {
+	Number     uint64
+	Hash       common.Hash
+	ParentHash common.Hash
+}
+
+// A change of the state of a pool, as logged by one of its events
+type Change interface {
+	// Validate checks the fields of the change, without a pool
+	Validate() error
+	// Apply returns the state of the pool after the change, the pool is not modified
+	Apply(pool *entities.Pool) (*entities.Pool, error)
+}
+
+/**
+ * A swap, replayed as an exact input swap of the amount paid into the pool. The price, liquidity and tick logged by
+ * the event are then set on the pool, so that the state does not depend on whether the swap was exact input or exact
+ * output, only the reinvestment liquidity being that of the replay.
+ */
+type Swap struct {
+	Amount0     *big.Int // The deltaQty0 of the Swap event, negative when taken out of the pool
+	Amount1     *big.Int // The deltaQty1 of the Swap event, negative when taken out of the pool
+	SqrtP       *big.Int // The sqrtP of the Swap event, optional, the replayed state is kept when nil
+	Liquidity   *big.Int // The liquidity of the Swap event, the base liquidity in range, set along SqrtP
+	CurrentTick int      // The currentTick of the Swap event, set along SqrtP
+}
+
+func (s *Swap) Validate() error {
+	if s.Amount0 == nil || s.Amount1 == nil || s.Amount0.Sign()*s.Amount1.Sign() >= 0 {
+		return ErrInvalidSwap
+	}
+	if s.SqrtP == nil {
+		if s.Liquidity != nil {
+			return ErrInvalidSwap
+		}
+		return nil
+	}
+	if s.Liquidity == nil || s.SqrtP.Sign() <= 0 || s.Liquidity.Sign() < 0 || s.CurrentTick < utils.MinTick || s.CurrentTick >= utils.MaxTick {
+		return ErrInvalidSwap
+	}
+	return nil
+}
+
+func (s *Swap) Apply(pool *entities.Pool) (*entities.Pool, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	amountIn := core.FromRawAmount(pool.Token0, s.Amount0)
+	if s.Amount1.Sign() > 0 {
+		amountIn = core.FromRawAmount(pool.Token1, s.Amount1)
+	}
+	result, err := pool.ApplySwap(amountIn, core.ExactInput, nil)
+	if err != nil {
+		return nil, err
+	}
+	if s.SqrtP == nil {
+		return result.Pool, nil
+	}
+	swapped := *result.Pool
+	swapped.SqrtP = s.SqrtP
+	swapped.BaseL = s.Liquidity
+	swapped.CurrentTick = s.CurrentTick
+	swapped.NearestCurrentTick = nearestInitializedTick(&swapped, s.CurrentTick)
+	return &swapped, nil
+}
+
+// nearestInitializedTick returns the greatest initialized tick at or below the tick, walking from the nearest tick of the pool
+func nearestInitializedTick(pool *entities.Pool, tick int) int {
+	nearest := pool.NearestCurrentTick
+	// the linked list ends with the MinTick and MaxTick sentinels, which point to themselves
+	for nearest > tick {
+		previous := pool.InitializedTicks[nearest].Previous
+		if previous == nearest {
+			break
+		}
+		nearest = previous
+	}
+	for {
+		next := pool.InitializedTicks[nearest].Next
+		if next > tick || next == nearest {
+			return nearest
+		}
+		nearest = next
+	}
+}
+
+// Liquidity added to a range
+type Mint struct {
+	TickLower int
+	TickUpper int
+	Liquidity *big.Int
+}
+
+func (m *Mint) Validate() error {
+	return validateLiquidity(m.Liquidity)
+}
+
+func (m *Mint) Apply(pool *entities.Pool) (*entities.Pool, error) {
+	if err := m.Validate(); err != nil {
+		return nil, err
+	}
+	return pool.ApplyLiquidity(m.TickLower, m.TickUpper, m.Liquidity)
+}
+
+// Liquidity removed from a range
+type Burn struct {
+	TickLower int
+	TickUpper int
+	Liquidity *big.Int
+}
+
+func (b *Burn) Validate() error {
+	return validateLiquidity(b.Liquidity)
+}
+
+func (b *Burn) Apply(pool *entities.Pool) (*entities.Pool, error) {
+	if err := b.Validate(); err != nil {
+		return nil, err
+	}
+	return pool.ApplyLiquidity(b.TickLower, b.TickUpper, new(big.Int).Neg(b.Liquidity))
+}
+
+func validateLiquidity(liquidity *big.Int) error {
+	if liquidity == nil || liquidity.Sign() <= 0 {
+		return ErrInvalidLiquidity
+	}
+	return nil
+}
+
+// The updates of the pools in a block
+type BlockUpdate struct {
+	Snapshots map[common.Address]*entities.Pool // The full states of pools as of the block, replacing their state
+	Changes   map[common.Address][]Change       // The changes of pools in the block, in log order, applied after the snapshots
+}
+
+type blockState struct {
+	block Block
+	pools map[common.Address]*entities.Pool
+}
+
+/**
+ * A PoolStore keeps the states of pools as of each of the latest blocks of the canonical chain. The pools served must
+ * not be modified, the states of consecutive blocks share the pools a block does not change.
+ */
+type PoolStore struct {
+	mu        sync.RWMutex
+	retention int
+	blocks    []*blockState // The canonical blocks retained, consecutive and oldest first
+}
+
+/**
+ * Constructs an empty store
+ * @param retention the number of latest blocks the states are kept for
+ */
+func NewPoolStore(retention int) (*PoolStore, error) {
+	if retention < 1 {
+		return nil, ErrInvalidRetention
+	}
+	return &PoolStore{retention: retention}, nil
+}
+
+/**
+ * Adds a block on top of its parent. When the parent is not the head but an earlier block, the blocks after the parent
+ * are reorged out and dropped. A block whose parent is not retained is refused, unless the store is empty: after a
+ * reorg deeper than the store retains, the store should be reset and filled with snapshots.
+ * @param block the block
+ * @param update the updates of the pools in the block
+ */
+func (s *PoolStore) AddBlock(block Block, update *BlockUpdate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pools := make(map[common.Address]*entities.Pool)
+	parent := -1
+	if len(s.blocks) > 0 {
+		parent = s.index(block.Number - 1)
+		if block.Number == 0 || parent < 0 || s.blocks[parent].block.Hash != block.ParentHash {
+			return fmt.Errorf("%w: %d %s", ErrUnknownParent, block.Number, block.Hash)
+		}
+		for address, pool := range s.blocks[parent].pools {
+			pools[address] = pool
+		}
+	}
+
+	if update != nil {
+		for address, pool := range update.Snapshots {
+			pools[address] = pool
+		}
+		for address, changes := range update.Changes {
+			pool, ok := pools[address]
+			if !ok {
+				return fmt.Errorf("%w: %s", ErrUnknownPool, address)
+			}
+			for _, change := range changes {
+				var err error
+				if pool, err = change.Apply(pool); err != nil {
+					return fmt.Errorf("pool %s in block %d: %w", address, block.Number, err)
+				}
+			}
+			pools[address] = pool
+		}
+	}
+
+	s.blocks = append(s.blocks[:parent+1], &blockState{block: block, pools: pools})
+	if len(s.blocks) > s.retention {
+		s.blocks = append([]*blockState(nil), s.blocks[len(s.blocks)-s.retention:]...)
+	}
+	return nil
+}
+
+// Rollback drops the blocks after the given block number, as when they are known to be reorged out
+func (s *PoolStore) Rollback(number uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, err := s.find(number)
+	if err != nil {
+		return err
+	}
+	s.blocks = s.blocks[:i+1]
+	return nil
+}
+
+// Head returns the latest block, false when the store is empty
+func (s *PoolStore) Head() (Block, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.blocks) == 0 {
+		return Block{}, false
+	}
+	return s.blocks[len(s.blocks)-1].block, true
+}
+
+// Latest returns the state of a pool as of the latest block, and the block
+func (s *PoolStore) Latest(address common.Address) (*entities.Pool, Block, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if len(s.blocks) == 0 {
+		return nil, Block{}, ErrBlockNotFound
+	}
+	state := s.blocks[len(s.blocks)-1]
+	pool, err := state.pool(address)
+	return pool, state.block, err
+}
+
+// PoolAt returns the state of a pool as of the canonical block of the given number
+func (s *PoolStore) PoolAt(address common.Address, number uint64) (*entities.Pool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i, err := s.find(number)
+	if err != nil {
+		return nil, err
+	}
+	return s.blocks[i].pool(address)
+}
+
+// PoolAtHash returns the state of a pool as of the block of the given hash, ErrBlockNotFound once it is reorged out
+func (s *PoolStore) PoolAtHash(address common.Address, hash common.Hash) (*entities.Pool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for i := len(s.blocks) - 1; i >= 0; i-- {
+		if s.blocks[i].block.Hash == hash {
+			return s.blocks[i].pool(address)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrBlockNotFound, hash)
+}
+
+// index returns the index of the block of the given number, -1 when it is not retained
+func (s *PoolStore) index(number uint64) int {
+	if len(s.blocks) == 0 || number < s.blocks[0].block.Number {
+		return -1
+	}
+	i := number - s.blocks[0].block.Number
+	if i >= uint64(len(s.blocks)) {
+		return -1
+	}
+	return int(i)
+}
+
+// find returns the index of the block of the given number, or why it is not retained
+func (s *PoolStore) find(number uint64) (int, error) {
+	i := s.index(number)
+	if i < 0 {
+		if len(s.blocks) > 0 && number < s.blocks[0].block.Number {
+			return 0, fmt.Errorf("%w: %d", ErrBlockPruned, number)
+		}
+		return 0, fmt.Errorf("%w: %d", ErrBlockNotFound, number)
+	}
+	return i, nil
+}
+
+func (b *blockState) pool(address common.Address) (*entities.Pool, error) {
+	pool, ok := b.pools[address]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownPool, address)
+	}
+	return pool, nil
+}
@@ -0,0 +1,176 @@
+package store
+
+import (
+	"math/big"
+	"testing"
+
+	core "github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/entities"
+	"github.com/KyberNetwork/elastic-go-sdk/v2/utils"
+)
+
+var (
+	token0   = core.NewToken(1, common.HexToAddress("0x0000000000000000000000000000000000000001"), 18, "t0", "token0")
+	token1   = core.NewToken(1, common.HexToAddress("0x0000000000000000000000000000000000000002"), 18, "t1", "token1")
+	oneEther = big.NewInt(1e18)
+	address  = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+)
+
+func newPool(t *testing.T) *entities.Pool {
+	tickSpacing := constants.TickSpacings[constants.Fee1]
+	ticks := []entities.Tick{
+		{Index: entities.NearestUsableTick(utils.MinTick, tickSpacing), LiquidityNet: oneEther, LiquidityGross: oneEther},
+		{Index: entities.NearestUsableTick(utils.MaxTick, tickSpacing), LiquidityNet: new(big.Int).Neg(oneEther), LiquidityGross: oneEther},
+	}
+	p, err := entities.NewTickListDataProvider(ticks, tickSpacing)
+	assert.NoError(t, err)
+	pool, err := entities.NewPool(token0, token1, constants.Fee1, utils.EncodeSqrtRatioX96(constants.One, constants.One), oneEther, big.NewInt(0), 0, p)
+	assert.NoError(t, err)
+	return pool
+}
+
+func block(number uint64, fork byte, parentFork byte) Block {
+	return Block{
+		Number:     number,
+		Hash:       common.BytesToHash([]byte{fork, byte(number)}),
+		ParentHash: common.BytesToHash([]byte{parentFork, byte(number - 1)}),
+	}
+}
+
+func swap(amount0, amount1 int64) *BlockUpdate {
+	return &BlockUpdate{Changes: map[common.Address][]Change{
+		address: {&Swap{Amount0: big.NewInt(amount0), Amount1: big.NewInt(amount1)}},
+	}}
+}
+
+func TestPoolStore(t *testing.T) {
+	_, err := NewPoolStore(0)
+	assert.ErrorIs(t, err, ErrInvalidRetention)
+
+	s, err := NewPoolStore(3)
+	assert.NoError(t, err)
+	_, ok := s.Head()
+	assert.False(t, ok)
+
+	pool := newPool(t)
+	assert.NoError(t, s.AddBlock(block(10, 1, 1), &BlockUpdate{Snapshots: map[common.Address]*entities.Pool{address: pool}}))
+	assert.NoError(t, s.AddBlock(block(11, 1, 1), swap(1e16, -1)))
+	assert.NoError(t, s.AddBlock(block(12, 1, 1), &BlockUpdate{Changes: map[common.Address][]Change{
+		address: {&Mint{TickLower: -400, TickUpper: 400, Liquidity: oneEther}, &Burn{TickLower: -400, TickUpper: 400, Liquidity: big.NewInt(1)}},
+	}}))
+
+	// the states of the previous blocks are kept as they were
+	at10, err := s.PoolAt(address, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, pool, at10)
+	at11, err := s.PoolAt(address, 11)
+	assert.NoError(t, err)
+	assert.Less(t, at11.CurrentTick, 0)
+	latest, head, err := s.Latest(address)
+	assert.NoError(t, err)
+	assert.Equal(t, block(12, 1, 1), head)
+	assert.Equal(t, new(big.Int).Sub(new(big.Int).Mul(oneEther, big.NewInt(2)), big.NewInt(1)), latest.BaseL)
+	assert.Equal(t, at11.SqrtP, latest.SqrtP)
+
+	// a reorg replaces the blocks after the common ancestor
+	assert.NoError(t, s.AddBlock(block(12, 2, 1), swap(-1, 1e16)))
+	assert.NoError(t, s.AddBlock(block(13, 2, 2), nil))
+	_, err = s.PoolAtHash(address, block(12, 1, 1).Hash)
+	assert.ErrorIs(t, err, ErrBlockNotFound)
+	at12, err := s.PoolAtHash(address, block(12, 2, 1).Hash)
+	assert.NoError(t, err)
+	assert.Greater(t, at12.SqrtP.Cmp(at11.SqrtP), 0)
+	assert.Equal(t, oneEther, at12.BaseL)
+	latest, head, err = s.Latest(address)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(13), head.Number)
+	assert.Equal(t, at12, latest)
+
+	// only the latest blocks are retained
+	_, err = s.PoolAt(address, 10)
+	assert.ErrorIs(t, err, ErrBlockPruned)
+	_, err = s.PoolAt(address, 14)
+	assert.ErrorIs(t, err, ErrBlockNotFound)
+	assert.ErrorIs(t, s.AddBlock(block(11, 3, 1), nil), ErrUnknownParent)
+	assert.ErrorIs(t, s.AddBlock(block(14, 2, 1), nil), ErrUnknownParent)
+
+	assert.NoError(t, s.Rollback(11))
+	head, _ = s.Head()
+	assert.Equal(t, uint64(11), head.Number)
+
+	// a failed update leaves the store as it was
+	assert.ErrorIs(t, s.AddBlock(block(12, 1, 1), &BlockUpdate{Changes: map[common.Address][]Change{
+		common.HexToAddress("0xb1"): {&Mint{TickLower: -400, TickUpper: 400, Liquidity: oneEther}},
+	}}), ErrUnknownPool)
+	assert.ErrorIs(t, s.AddBlock(block(12, 1, 1), swap(1, 1)), ErrInvalidSwap)
+	assert.ErrorIs(t, s.AddBlock(block(12, 1, 1), &BlockUpdate{Changes: map[common.Address][]Change{
+		address: {&Burn{TickLower: -400, TickUpper: 400, Liquidity: oneEther}},
+	}}), entities.ErrLiquidityUnderflow)
+	head, _ = s.Head()
+	assert.Equal(t, uint64(11), head.Number)
+}
+
+func TestSwapEventState(t *testing.T) {
+	pool, err := (&Mint{TickLower: -400, TickUpper: 400, Liquidity: oneEther}).Apply(newPool(t))
+	assert.NoError(t, err)
+
+	// the state logged by the event is set on the pool
+	sqrtP, err := utils.GetSqrtRatioAtTick(-100)
+	assert.NoError(t, err)
+	liquidity := new(big.Int).Mul(oneEther, big.NewInt(2))
+	swapped, err := (&Swap{Amount0: big.NewInt(1e16), Amount1: big.NewInt(-1), SqrtP: sqrtP, Liquidity: liquidity, CurrentTick: -100}).Apply(pool)
+	assert.NoError(t, err)
+	assert.Equal(t, sqrtP, swapped.SqrtP)
+	assert.Equal(t, liquidity, swapped.BaseL)
+	assert.Equal(t, -100, swapped.CurrentTick)
+	assert.Equal(t, -400, swapped.NearestCurrentTick)
+
+	replayed, err := (&Swap{Amount0: big.NewInt(1e16), Amount1: big.NewInt(-1)}).Apply(pool)
+	assert.NoError(t, err)
+	assert.Equal(t, replayed.ReinvestL, swapped.ReinvestL)
+
+	// the nearest tick follows the logged tick across initialized ticks
+	sqrtP, err = utils.GetSqrtRatioAtTick(-500)
+	assert.NoError(t, err)
+	swapped, err = (&Swap{Amount0: big.NewInt(1e16), Amount1: big.NewInt(-1), SqrtP: sqrtP, Liquidity: oneEther, CurrentTick: -500}).Apply(pool)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.NearestUsableTick(utils.MinTick, constants.TickSpacings[constants.Fee1]), swapped.NearestCurrentTick)
+	sqrtP, err = utils.GetSqrtRatioAtTick(400)
+	assert.NoError(t, err)
+	swapped, err = (&Swap{Amount0: big.NewInt(-1), Amount1: big.NewInt(1e16), SqrtP: sqrtP, Liquidity: oneEther, CurrentTick: 400}).Apply(pool)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, swapped.NearestCurrentTick)
+
+	_, err = (&Swap{Amount0: big.NewInt(1e16), Amount1: big.NewInt(-1), SqrtP: sqrtP}).Apply(pool)
+	assert.ErrorIs(t, err, ErrInvalidSwap)
+}
+
+func TestChangeValidate(t *testing.T) {
+	pool := newPool(t)
+	for _, change := range []Change{
+		&Swap{Amount0: big.NewInt(1)},
+		&Swap{Amount1: big.NewInt(-1)},
+		&Swap{Amount0: big.NewInt(1), Amount1: big.NewInt(-1), Liquidity: oneEther},
+		&Swap{Amount0: big.NewInt(1), Amount1: big.NewInt(-1), SqrtP: big.NewInt(0), Liquidity: oneEther},
+		&Swap{Amount0: big.NewInt(1), Amount1: big.NewInt(-1), SqrtP: oneEther, Liquidity: oneEther, CurrentTick: utils.MaxTick},
+	} {
+		assert.ErrorIs(t, change.Validate(), ErrInvalidSwap)
+		_, err := change.Apply(pool)
+		assert.ErrorIs(t, err, ErrInvalidSwap)
+	}
+	for _, change := range []Change{
+		&Mint{TickLower: -400, TickUpper: 400},
+		&Burn{TickLower: -400, TickUpper: 400},
+		&Mint{TickLower: -400, TickUpper: 400, Liquidity: big.NewInt(0)},
+		&Burn{TickLower: -400, TickUpper: 400, Liquidity: big.NewInt(-1)},
+	} {
+		assert.ErrorIs(t, change.Validate(), ErrInvalidLiquidity)
+		_, err := change.Apply(pool)
+		assert.ErrorIs(t, err, ErrInvalidLiquidity)
+	}
+	assert.NoError(t, (&Swap{Amount0: big.NewInt(1), Amount1: big.NewInt(-1)}).Validate())
+}
@@ -2,22 +2,29 @@ package utils
 
 import (
 	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 type MethodParameters struct {
-	Calldata []byte   // The hex encoded calldata to perform the given operation
-	Value    *big.Int // The amount of ether (wei) to send in hex
+	Calldata []byte          // The hex encoded calldata to perform the given operation
+	Value    *big.Int        // The amount of ether (wei) to send in hex
+	PreCalls []*ContractCall // The calls to other contracts to execute before the operation, e.g. Permit2 approvals
+}
+
+// A call to a contract other than the one the method parameters are for
+type ContractCall struct {
+	To       common.Address // The contract to call
+	Calldata []byte         // The calldata of the call
+	Value    *big.Int       // The amount of ether (wei) to send
 }
 
 /**
- * Converts a big int to a hex string
+ * Converts a big int to a hex quantity as JSON-RPC expects it, without leading zeros
  * @param bigintIsh
- * @returns The hex encoded calldata
+ * @returns The hex encoded quantity
  */
 func ToHex(i *big.Int) string {
-	hex := i.String()
-	if len(hex)%2 != 0 {
-		hex = "0" + hex
-	}
-	return "0x" + hex
+	return hexutil.EncodeBig(i)
 }
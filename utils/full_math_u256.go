@@ -0,0 +1,138 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/holiman/uint256"
+)
+
+// ErrUint256Overflow is returned by the fixed-width math when a value does not fit in 256 bits, or is negative where
+// the big.Int math would keep a signed value. The big.Int math gives the result of such inputs.
+var ErrUint256Overflow = errors.New("value does not fit in 256 bits")
+
+// The overflows of FullMath and SafeCast, told apart from the others to report the right revert in strict mode
+var (
+	errMulDivOverflow        = fmt.Errorf("%w: mulDivFloor", ErrUint256Overflow)
+	errMulDivCeilingOverflow = fmt.Errorf("%w: mulDivCeiling", ErrUint256Overflow)
+	errInt256Overflow        = fmt.Errorf("%w: toInt256", ErrUint256Overflow)
+)
+
+var (
+	u256Zero        = uint256.NewInt(0)
+	u256One         = uint256.NewInt(1)
+	u256Q96         = new(uint256.Int).Lsh(uint256.NewInt(1), 96)
+	u256FeeUnits    = uint256.NewInt(100000)
+	u256TwoFeeUnits = uint256.NewInt(200000)
+	u256MaxInt256   = new(uint256.Int).Sub(new(uint256.Int).Lsh(uint256.NewInt(1), 255), uint256.NewInt(1))
+)
+
+// MulDivU256 returns floor(a * b / denominator) with a 512-bit intermediate product
+func MulDivU256(a, b, denominator *uint256.Int) (*uint256.Int, error) {
+	if denominator.IsZero() {
+		return nil, errMulDivOverflow
+	}
+	z, overflow := new(uint256.Int).MulDivOverflow(a, b, denominator)
+	if overflow {
+		return nil, errMulDivOverflow
+	}
+	return z, nil
+}
+
+// MulDivRoundingUpU256 returns ceil(a * b / denominator) with a 512-bit intermediate product
+func MulDivRoundingUpU256(a, b, denominator *uint256.Int) (*uint256.Int, error) {
+	z, err := MulDivU256(a, b, denominator)
+	if err != nil {
+		return nil, err
+	}
+	if !new(uint256.Int).MulMod(a, b, denominator).IsZero() {
+		var overflow bool
+		if z, overflow = z.AddOverflow(z, u256One); overflow {
+			return nil, errMulDivCeilingOverflow
+		}
+	}
+	return z, nil
+}
+
+// GetSmallerRootOfQuadEqnU256 returns (b - sqrt(b * b - a * c)) / a
+func GetSmallerRootOfQuadEqnU256(a, b, c *uint256.Int) (*uint256.Int, error) {
+	bb, overflow := new(uint256.Int).MulOverflow(b, b)
+	if overflow {
+		return nil, ErrUint256Overflow
+	}
+	ac, overflow := new(uint256.Int).MulOverflow(a, c)
+	if overflow {
+		return nil, ErrUint256Overflow
+	}
+	discriminant, underflow := new(uint256.Int).SubOverflow(bb, ac)
+	if underflow || a.IsZero() {
+		return nil, ErrUint256Overflow
+	}
+	root := new(uint256.Int).Sqrt(discriminant)
+	return root.Div(root.Sub(b, root), a), nil
+}
+
+// U256FromBig converts a non-negative big.Int to a 256-bit integer
+func U256FromBig(x *big.Int) (*uint256.Int, error) {
+	if x.Sign() < 0 {
+		return nil, ErrUint256Overflow
+	}
+	z, overflow := uint256.FromBig(x)
+	if overflow {
+		return nil, ErrUint256Overflow
+	}
+	return z, nil
+}
+
+// I256FromBig converts a big.Int to a two's complement 256-bit integer, the representation of int256
+func I256FromBig(x *big.Int) (*uint256.Int, error) {
+	z, err := U256FromBig(new(big.Int).Abs(x))
+	if err != nil || z.Gt(u256MaxInt256) {
+		return nil, ErrUint256Overflow
+	}
+	if x.Sign() < 0 {
+		z.Neg(z)
+	}
+	return z, nil
+}
+
+// I256ToBig converts a two's complement 256-bit integer to a big.Int
+func I256ToBig(x *uint256.Int) *big.Int {
+	if x.Sign() >= 0 {
+		return x.ToBig()
+	}
+	return new(big.Int).Neg(new(uint256.Int).Neg(x).ToBig())
+}
+
+// subI256 returns a - b as an int256, both being non-negative and below 2^255
+func subI256(a, b *uint256.Int) (*uint256.Int, error) {
+	if a.Gt(u256MaxInt256) || b.Gt(u256MaxInt256) {
+		return nil, errInt256Overflow
+	}
+	return new(uint256.Int).Sub(a, b), nil
+}
+
+func addU256(a, b *uint256.Int) (*uint256.Int, error) {
+	z, overflow := new(uint256.Int).AddOverflow(a, b)
+	if overflow {
+		return nil, ErrUint256Overflow
+	}
+	return z, nil
+}
+
+func subU256(a, b *uint256.Int) (*uint256.Int, error) {
+	z, underflow := new(uint256.Int).SubOverflow(a, b)
+	if underflow {
+		return nil, ErrUint256Overflow
+	}
+	return z, nil
+}
+
+func mulU256(a, b *uint256.Int) (*uint256.Int, error) {
+	z, overflow := new(uint256.Int).MulOverflow(a, b)
+	if overflow {
+		return nil, ErrUint256Overflow
+	}
+	return z, nil
+}
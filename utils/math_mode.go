@@ -0,0 +1,143 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+)
+
+// The integer arithmetic used for tick, sqrt price and swap math
+type MathMode int
+
+const (
+	MathBigInt  MathMode = iota // Arbitrary precision big.Int math, the default
+	MathUint256                 // Fixed-width 256-bit math, falling back to big.Int for values it cannot represent
+	MathStrict                  // Fixed-width 256-bit math returning a RevertError wherever the contracts revert
+)
+
+// GetSqrtRatioAtTick returns the same result as GetSqrtRatioAtTick, computed with the arithmetic of the mode
+func (m MathMode) GetSqrtRatioAtTick(tick int) (*big.Int, error) {
+	if m == MathBigInt {
+		return GetSqrtRatioAtTick(tick)
+	}
+	ratio, err := GetSqrtRatioAtTickU256(tick)
+	if err != nil {
+		return nil, err
+	}
+	return ratio.ToBig(), nil
+}
+
+// GetTickAtSqrtRatio returns the same result as GetTickAtSqrtRatio, computed with the arithmetic of the mode
+func (m MathMode) GetTickAtSqrtRatio(sqrtRatioX96 *big.Int) (int, error) {
+	if m == MathBigInt {
+		return GetTickAtSqrtRatio(sqrtRatioX96)
+	}
+	x, err := U256FromBig(sqrtRatioX96)
+	if err != nil {
+		return GetTickAtSqrtRatio(sqrtRatioX96)
+	}
+	return GetTickAtSqrtRatioU256(x)
+}
+
+/**
+ * ComputeSwapStep returns the same results as ComputeSwapStep, computed with the arithmetic of the mode. In strict
+ * mode, the steps the contract reverts on return the revert instead.
+ */
+func (m MathMode) ComputeSwapStep(
+	liquidity *big.Int,
+	currentSqrtP *big.Int,
+	targetSqrtP *big.Int,
+	feeInFeeUnits constants.FeeAmount,
+	specifiedAmount *big.Int,
+	isExactInput bool,
+	isToken0 bool,
+) (
+	usedAmount *big.Int,
+	returnedAmount *big.Int,
+	deltaL *big.Int,
+	nextSqrtP *big.Int,
+	err error,
+) {
+	// the step is a no-op when the prices are equal, callers rely on getting currentSqrtP back
+	if m == MathBigInt || currentSqrtP.Cmp(targetSqrtP) == 0 {
+		return ComputeSwapStep(liquidity, currentSqrtP, targetSqrtP, feeInFeeUnits, specifiedAmount, isExactInput, isToken0)
+	}
+	if m == MathStrict {
+		// the pool passes the sum of its uint128 liquidities, which fits in 160 bits
+		if !fitsUint(liquidity, MaxUint160) || !fitsUint(currentSqrtP, MaxUint160) || !fitsUint(targetSqrtP, MaxUint160) ||
+			specifiedAmount.Cmp(MinInt256) < 0 || specifiedAmount.Cmp(MaxInt256) > 0 {
+			return nil, nil, nil, nil, ErrRevertArithmetic
+		}
+	}
+	used, returned, dL, next, err := computeSwapStepFromBig(liquidity, currentSqrtP, targetSqrtP, feeInFeeUnits, specifiedAmount, isExactInput, isToken0)
+	if m == MathStrict && err != nil {
+		return nil, nil, nil, nil, revertOf(err)
+	}
+	if m == MathStrict && next.Gt(u256MaxUint160) {
+		return nil, nil, nil, nil, ErrRevertSafeCastUint160
+	}
+	if errors.Is(err, ErrUint256Overflow) {
+		return ComputeSwapStep(liquidity, currentSqrtP, targetSqrtP, feeInFeeUnits, specifiedAmount, isExactInput, isToken0)
+	}
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return I256ToBig(used), I256ToBig(returned), dL.ToBig(), next.ToBig(), nil
+}
+
+/**
+ * ApplyLiquidityDelta returns the same result as ApplyLiquidityDelta. In strict mode, the liquidities are uint128 values
+ * and over or underflows return the revert.
+ */
+func (m MathMode) ApplyLiquidityDelta(liquidity, liquidityDelta *big.Int, isAddLiquidity bool) (*big.Int, error) {
+	if m == MathStrict {
+		return ApplyLiquidityDeltaStrict(liquidity, liquidityDelta, isAddLiquidity)
+	}
+	return ApplyLiquidityDelta(liquidity, liquidityDelta, isAddLiquidity), nil
+}
+
+// revertOf returns the revert of the contract for an overflow of the fixed-width math
+func revertOf(err error) error {
+	switch {
+	case errors.Is(err, errMulDivOverflow):
+		return ErrRevertMulDiv
+	case errors.Is(err, errMulDivCeilingOverflow):
+		return ErrRevertMulDivCeiling
+	case errors.Is(err, errInt256Overflow):
+		return ErrRevertSafeCastInt256
+	case errors.Is(err, ErrUint256Overflow):
+		return ErrRevertArithmetic
+	}
+	return err
+}
+
+func computeSwapStepFromBig(
+	liquidity *big.Int,
+	currentSqrtP *big.Int,
+	targetSqrtP *big.Int,
+	feeInFeeUnits constants.FeeAmount,
+	specifiedAmount *big.Int,
+	isExactInput bool,
+	isToken0 bool,
+) (usedAmount, returnedAmount, deltaL, nextSqrtP *uint256.Int, err error) {
+	l, err := U256FromBig(liquidity)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	current, err := U256FromBig(currentSqrtP)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	target, err := U256FromBig(targetSqrtP)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	specified, err := I256FromBig(specifiedAmount)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return ComputeSwapStepU256(l, current, target, feeInFeeUnits, specified, isExactInput, isToken0)
+}
@@ -0,0 +1,214 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/holiman/uint256"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+)
+
+var testFeeAmounts = []constants.FeeAmount{
+	constants.Fee0008, constants.Fee001, constants.Fee002, constants.Fee004, constants.Fee01,
+	constants.Fee025, constants.Fee03, constants.Fee1, constants.Fee2, constants.Fee5,
+}
+
+func randomBits(r *rand.Rand, bits int) *big.Int {
+	return new(big.Int).Rand(r, new(big.Int).Lsh(constants.One, uint(bits)))
+}
+
+type swapStepCase struct {
+	liquidity, currentSqrtP, targetSqrtP, specifiedAmount *big.Int
+	fee                                                   constants.FeeAmount
+	isExactInput, isToken0                                bool
+}
+
+// randomSwapStep returns a step as the pool simulates it, the target at most MaxTickDistance ticks away
+func randomSwapStep(r *rand.Rand) swapStepCase {
+	tick := MinTick + 480 + r.Intn(MaxTick-MinTick-960)
+	isExactInput, isToken0 := r.Intn(2) == 0, r.Intn(2) == 0
+	targetTick := tick - r.Intn(480) - 1
+	if isExactInput != isToken0 {
+		targetTick = tick + r.Intn(480) + 1
+	}
+	currentSqrtP, _ := GetSqrtRatioAtTick(tick)
+	currentSqrtP.Add(currentSqrtP, randomBits(r, 16))
+	targetSqrtP, _ := GetSqrtRatioAtTick(targetTick)
+
+	specifiedAmount := new(big.Int).Add(randomBits(r, 1+r.Intn(120)), constants.One)
+	if !isExactInput {
+		specifiedAmount.Neg(specifiedAmount)
+	}
+	return swapStepCase{
+		liquidity:       new(big.Int).Add(randomBits(r, 40+r.Intn(88)), constants.One),
+		currentSqrtP:    currentSqrtP,
+		targetSqrtP:     targetSqrtP,
+		specifiedAmount: specifiedAmount,
+		fee:             testFeeAmounts[r.Intn(len(testFeeAmounts))],
+		isExactInput:    isExactInput,
+		isToken0:        isToken0,
+	}
+}
+
+func TestMathModeGetSqrtRatioAtTick(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	ticks := []int{MinTick, MinTick + 1, -1, 0, 1, MaxTick - 1, MaxTick}
+	for i := 0; i < 5000; i++ {
+		ticks = append(ticks, MinTick+r.Intn(MaxTick-MinTick+1))
+	}
+	for _, tick := range ticks {
+		expected, _ := GetSqrtRatioAtTick(tick)
+		actual, err := MathUint256.GetSqrtRatioAtTick(tick)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, expected.Cmp(actual), "tick %d", tick)
+	}
+
+	_, err := MathUint256.GetSqrtRatioAtTick(MaxTick + 1)
+	assert.ErrorIs(t, err, ErrInvalidTick)
+}
+
+func TestMathModeGetTickAtSqrtRatio(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	ratios := []*big.Int{MinSqrtRatio, new(big.Int).Sub(MaxSqrtRatio, constants.One), new(big.Int).Lsh(constants.One, 96)}
+	for i := 0; i < 5000; i++ {
+		ratio := randomBits(r, 1+r.Intn(160))
+		if ratio.Cmp(MinSqrtRatio) >= 0 && ratio.Cmp(MaxSqrtRatio) < 0 {
+			ratios = append(ratios, ratio)
+		}
+		// the ratios at and around initialized ticks
+		tick := MinTick + r.Intn(MaxTick-MinTick)
+		atTick, _ := GetSqrtRatioAtTick(tick)
+		ratios = append(ratios, atTick, new(big.Int).Add(atTick, constants.One))
+	}
+	for _, ratio := range ratios {
+		expected, _ := GetTickAtSqrtRatio(ratio)
+		actual, err := MathUint256.GetTickAtSqrtRatio(ratio)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, actual, "ratio %s", ratio)
+	}
+
+	for _, ratio := range []*big.Int{new(big.Int).Sub(MinSqrtRatio, constants.One), MaxSqrtRatio, big.NewInt(-1)} {
+		_, err := MathUint256.GetTickAtSqrtRatio(ratio)
+		assert.ErrorIs(t, err, ErrInvalidSqrtRatio)
+	}
+}
+
+func TestMathModeComputeSwapStep(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	fallbacks := 0
+	for i := 0; i < 20000; i++ {
+		c := randomSwapStep(r)
+		used, returned, deltaL, next, err := ComputeSwapStep(c.liquidity, c.currentSqrtP, c.targetSqrtP, c.fee, c.specifiedAmount, c.isExactInput, c.isToken0)
+		assert.NoError(t, err)
+		used256, returned256, deltaL256, next256, err := MathUint256.ComputeSwapStep(c.liquidity, c.currentSqrtP, c.targetSqrtP, c.fee, c.specifiedAmount, c.isExactInput, c.isToken0)
+		assert.NoError(t, err)
+		if _, _, _, _, err := computeSwapStepFromBig(c.liquidity, c.currentSqrtP, c.targetSqrtP, c.fee, c.specifiedAmount, c.isExactInput, c.isToken0); err != nil {
+			fallbacks++
+		}
+		assert.Equal(t, 0, used.Cmp(used256), "used amount of %+v", c)
+		assert.Equal(t, 0, returned.Cmp(returned256), "returned amount of %+v", c)
+		assert.Equal(t, 0, deltaL.Cmp(deltaL256), "deltaL of %+v", c)
+		assert.Equal(t, 0, next.Cmp(next256), "next sqrt price of %+v", c)
+	}
+	t.Logf("%d steps fell back to big.Int math", fallbacks)
+
+	// the step is a no-op when the target is the current price
+	current := new(big.Int).Lsh(constants.One, 96)
+	used, returned, _, _, err := MathUint256.ComputeSwapStep(big.NewInt(1), current, current, constants.Fee1, big.NewInt(10), true, true)
+	assert.NoError(t, err)
+	assert.Same(t, current, used)
+	assert.Equal(t, 0, returned.Sign())
+
+	// amounts beyond int256 fall back to big.Int math
+	huge := new(big.Int).Lsh(constants.One, 300)
+	target := new(big.Int).Sub(current, big.NewInt(1000))
+	expected, _, _, _, _ := ComputeSwapStep(big.NewInt(1e18), current, target, constants.Fee1, huge, true, true)
+	actual, _, _, _, err := MathUint256.ComputeSwapStep(big.NewInt(1e18), current, target, constants.Fee1, huge, true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestMathModeSqrtPriceMath(t *testing.T) {
+	r := rand.New(rand.NewSource(4))
+	for i := 0; i < 5000; i++ {
+		sqrtA, _ := GetSqrtRatioAtTick(MinTick + r.Intn(MaxTick-MinTick))
+		sqrtB, _ := GetSqrtRatioAtTick(MinTick + r.Intn(MaxTick-MinTick))
+		liquidity := randomBits(r, 1+r.Intn(127))
+		roundUp := r.Intn(2) == 0
+
+		a, b, l := uint256.MustFromBig(sqrtA), uint256.MustFromBig(sqrtB), uint256.MustFromBig(liquidity)
+		if amount0, err := GetAmount0DeltaU256(a, b, l, roundUp); err == nil {
+			assert.Equal(t, 0, GetAmount0Delta(sqrtA, sqrtB, liquidity, roundUp).Cmp(amount0.ToBig()))
+		}
+		if amount1, err := GetAmount1DeltaU256(a, b, l, roundUp); err == nil {
+			assert.Equal(t, 0, GetAmount1Delta(sqrtA, sqrtB, liquidity, roundUp).Cmp(amount1.ToBig()))
+		}
+
+		amount := randomBits(r, 1+r.Intn(100))
+		zeroForOne := r.Intn(2) == 0
+		expected, expectedErr := GetNextSqrtPriceFromInput(sqrtA, liquidity, amount, zeroForOne)
+		if actual, err := GetNextSqrtPriceFromInputU256(a, l, uint256.MustFromBig(amount), zeroForOne); !errors.Is(err, ErrUint256Overflow) {
+			assert.Equal(t, expectedErr, err)
+			if err == nil {
+				assert.Equal(t, 0, expected.Cmp(actual.ToBig()))
+			}
+		}
+		expected, expectedErr = GetNextSqrtPriceFromOutput(sqrtA, liquidity, amount, zeroForOne)
+		if actual, err := GetNextSqrtPriceFromOutputU256(a, l, uint256.MustFromBig(amount), zeroForOne); !errors.Is(err, ErrUint256Overflow) {
+			assert.Equal(t, expectedErr, err)
+			if err == nil {
+				assert.Equal(t, 0, expected.Cmp(actual.ToBig()))
+			}
+		}
+	}
+}
+
+func BenchmarkGetSqrtRatioAtTick(b *testing.B) {
+	for _, mode := range []struct {
+		name string
+		math MathMode
+	}{{"BigInt", MathBigInt}, {"Uint256", MathUint256}} {
+		b.Run(mode.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = mode.math.GetSqrtRatioAtTick(MinTick + i%(MaxTick-MinTick))
+			}
+		})
+	}
+}
+
+func BenchmarkGetTickAtSqrtRatio(b *testing.B) {
+	ratio, _ := new(big.Int).SetString("1461446703485210103287273052203988822378723970341", 10)
+	for _, mode := range []struct {
+		name string
+		math MathMode
+	}{{"BigInt", MathBigInt}, {"Uint256", MathUint256}} {
+		b.Run(mode.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				_, _ = mode.math.GetTickAtSqrtRatio(ratio)
+			}
+		})
+	}
+}
+
+func BenchmarkComputeSwapStep(b *testing.B) {
+	r := rand.New(rand.NewSource(5))
+	steps := make([]swapStepCase, 1024)
+	for i := range steps {
+		steps[i] = randomSwapStep(r)
+	}
+	for _, mode := range []struct {
+		name string
+		math MathMode
+	}{{"BigInt", MathBigInt}, {"Uint256", MathUint256}} {
+		b.Run(mode.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				c := steps[i%len(steps)]
+				_, _, _, _, _ = mode.math.ComputeSwapStep(c.liquidity, c.currentSqrtP, c.targetSqrtP, c.fee, c.specifiedAmount, c.isExactInput, c.isToken0)
+			}
+		})
+	}
+}
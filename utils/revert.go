@@ -0,0 +1,43 @@
+package utils
+
+import "fmt"
+
+// Solidity panic codes, the revert data of failed compiler checks
+const (
+	PanicArithmetic     uint64 = 0x11 // Checked arithmetic under or overflowed
+	PanicDivisionByZero uint64 = 0x12 // Division or modulo by zero
+)
+
+/**
+ * The revert of a contract call, returned by the strict math where the contracts revert. Reverts of a require without a
+ * message carry no data on-chain, Source tells where the SDK found them.
+ */
+type RevertError struct {
+	Source string // The library function reverting
+	Reason string // The message of the require, empty if it has none
+	Panic  uint64 // The panic code of a failed compiler check, 0 for a require
+}
+
+func (e *RevertError) Error() string {
+	switch {
+	case e.Panic != 0:
+		return fmt.Sprintf("%s: panic 0x%x", e.Source, e.Panic)
+	case e.Reason != "":
+		return fmt.Sprintf("%s: execution reverted: %s", e.Source, e.Reason)
+	default:
+		return fmt.Sprintf("%s: execution reverted", e.Source)
+	}
+}
+
+var (
+	ErrRevertArithmetic      = &RevertError{Source: "checked arithmetic", Panic: PanicArithmetic}
+	ErrRevertDivisionByZero  = &RevertError{Source: "checked arithmetic", Panic: PanicDivisionByZero}
+	ErrRevertMulDiv          = &RevertError{Source: "FullMath.mulDivFloor"}
+	ErrRevertMulDivCeiling   = &RevertError{Source: "FullMath.mulDivCeiling"}
+	ErrRevertSafeCastUint128 = &RevertError{Source: "SafeCast.toUint128"}
+	ErrRevertSafeCastUint160 = &RevertError{Source: "SafeCast.toUint160"}
+	ErrRevertSafeCastInt256  = &RevertError{Source: "SafeCast.toInt256"}
+	ErrRevertLiquidityDelta  = &RevertError{Source: "LiqDeltaMath.applyLiquidityDelta", Panic: PanicArithmetic}
+	ErrRevertSafeMathAdd     = &RevertError{Source: "LowGasSafeMath.add"}
+	ErrRevertAmount0Delta    = &RevertError{Source: "SqrtPriceMath.getAmount0Delta"}
+)
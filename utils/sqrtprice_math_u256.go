@@ -0,0 +1,124 @@
+package utils
+
+import (
+	"github.com/holiman/uint256"
+)
+
+var u256MaxUint160 = uint256.MustFromBig(MaxUint160)
+
+// GetAmount0DeltaU256 is GetAmount0Delta on 256-bit integers
+func GetAmount0DeltaU256(sqrtRatioAX96, sqrtRatioBX96, liquidity *uint256.Int, roundUp bool) (*uint256.Int, error) {
+	if !sqrtRatioAX96.Lt(sqrtRatioBX96) {
+		sqrtRatioAX96, sqrtRatioBX96 = sqrtRatioBX96, sqrtRatioAX96
+	}
+	if liquidity.BitLen() > 160 {
+		return nil, ErrUint256Overflow
+	}
+	numerator1 := new(uint256.Int).Lsh(liquidity, 96)
+	numerator2 := new(uint256.Int).Sub(sqrtRatioBX96, sqrtRatioAX96)
+
+	if roundUp {
+		amount, err := MulDivRoundingUpU256(numerator1, numerator2, sqrtRatioBX96)
+		if err != nil {
+			return nil, err
+		}
+		return MulDivRoundingUpU256(amount, u256One, sqrtRatioAX96)
+	}
+	amount, err := MulDivU256(numerator1, numerator2, sqrtRatioBX96)
+	if err != nil || sqrtRatioAX96.IsZero() {
+		return nil, ErrUint256Overflow
+	}
+	return amount.Div(amount, sqrtRatioAX96), nil
+}
+
+// GetAmount1DeltaU256 is GetAmount1Delta on 256-bit integers
+func GetAmount1DeltaU256(sqrtRatioAX96, sqrtRatioBX96, liquidity *uint256.Int, roundUp bool) (*uint256.Int, error) {
+	if !sqrtRatioAX96.Lt(sqrtRatioBX96) {
+		sqrtRatioAX96, sqrtRatioBX96 = sqrtRatioBX96, sqrtRatioAX96
+	}
+	diff := new(uint256.Int).Sub(sqrtRatioBX96, sqrtRatioAX96)
+	if roundUp {
+		return MulDivRoundingUpU256(liquidity, diff, u256Q96)
+	}
+	return MulDivU256(liquidity, diff, u256Q96)
+}
+
+// GetNextSqrtPriceFromInputU256 is GetNextSqrtPriceFromInput on 256-bit integers
+func GetNextSqrtPriceFromInputU256(sqrtPX96, liquidity, amountIn *uint256.Int, zeroForOne bool) (*uint256.Int, error) {
+	if sqrtPX96.IsZero() {
+		return nil, ErrSqrtPriceLessThanZero
+	}
+	if liquidity.IsZero() {
+		return nil, ErrLiquidityLessThanZero
+	}
+	if zeroForOne {
+		return getNextSqrtPriceFromAmount0RoundingUpU256(sqrtPX96, liquidity, amountIn, true)
+	}
+	return getNextSqrtPriceFromAmount1RoundingDownU256(sqrtPX96, liquidity, amountIn, true)
+}
+
+// GetNextSqrtPriceFromOutputU256 is GetNextSqrtPriceFromOutput on 256-bit integers
+func GetNextSqrtPriceFromOutputU256(sqrtPX96, liquidity, amountOut *uint256.Int, zeroForOne bool) (*uint256.Int, error) {
+	if sqrtPX96.IsZero() {
+		return nil, ErrSqrtPriceLessThanZero
+	}
+	if liquidity.IsZero() {
+		return nil, ErrLiquidityLessThanZero
+	}
+	if zeroForOne {
+		return getNextSqrtPriceFromAmount1RoundingDownU256(sqrtPX96, liquidity, amountOut, false)
+	}
+	return getNextSqrtPriceFromAmount0RoundingUpU256(sqrtPX96, liquidity, amountOut, false)
+}
+
+func getNextSqrtPriceFromAmount0RoundingUpU256(sqrtPX96, liquidity, amount *uint256.Int, add bool) (*uint256.Int, error) {
+	if amount.IsZero() {
+		return sqrtPX96, nil
+	}
+	if liquidity.BitLen() > 160 {
+		return nil, ErrUint256Overflow
+	}
+
+	numerator1 := new(uint256.Int).Lsh(liquidity, 96)
+	// the product wraps like in multiplyIn256
+	product, overflow := new(uint256.Int).MulOverflow(amount, sqrtPX96)
+	if add {
+		if !overflow {
+			denominator, overflow := new(uint256.Int).AddOverflow(numerator1, product)
+			if !overflow {
+				return MulDivRoundingUpU256(numerator1, sqrtPX96, denominator)
+			}
+		}
+		denominator, err := addU256(new(uint256.Int).Div(numerator1, sqrtPX96), amount)
+		if err != nil {
+			return nil, err
+		}
+		return MulDivRoundingUpU256(numerator1, u256One, denominator)
+	}
+	if overflow {
+		return nil, ErrInvariant
+	}
+	if !numerator1.Gt(product) {
+		return nil, ErrInvariant
+	}
+	return MulDivRoundingUpU256(numerator1, sqrtPX96, new(uint256.Int).Sub(numerator1, product))
+}
+
+func getNextSqrtPriceFromAmount1RoundingDownU256(sqrtPX96, liquidity, amount *uint256.Int, add bool) (*uint256.Int, error) {
+	if add {
+		quotient, err := MulDivU256(amount, u256Q96, liquidity)
+		if err != nil {
+			return nil, err
+		}
+		return addU256(sqrtPX96, quotient)
+	}
+
+	quotient, err := MulDivRoundingUpU256(amount, u256Q96, liquidity)
+	if err != nil {
+		return nil, err
+	}
+	if !sqrtPX96.Gt(quotient) {
+		return nil, ErrInvariant
+	}
+	return new(uint256.Int).Sub(sqrtPX96, quotient), nil
+}
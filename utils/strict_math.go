@@ -0,0 +1,190 @@
+package utils
+
+import (
+	"math/big"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+)
+
+var (
+	MaxUint128 = new(big.Int).Sub(new(big.Int).Lsh(constants.One, 128), constants.One)
+	MaxInt256  = new(big.Int).Sub(new(big.Int).Lsh(constants.One, 255), constants.One)
+	MinInt256  = new(big.Int).Neg(new(big.Int).Lsh(constants.One, 255))
+)
+
+// fitsUint returns whether x is a value of a uint type with the given maximum
+func fitsUint(x, max *big.Int) bool {
+	return x.Sign() >= 0 && x.Cmp(max) <= 0
+}
+
+// MulDivStrict is MulDiv reverting like FullMath.mulDivFloor, when the denominator is zero or the result overflows
+func MulDivStrict(a, b, denominator *big.Int) (*big.Int, error) {
+	if !fitsUint(a, entities.MaxUint256) || !fitsUint(b, entities.MaxUint256) || !fitsUint(denominator, entities.MaxUint256) {
+		return nil, ErrRevertArithmetic
+	}
+	if denominator.Sign() == 0 {
+		return nil, ErrRevertMulDiv
+	}
+	result := MulDiv(a, b, denominator)
+	if result.Cmp(entities.MaxUint256) > 0 {
+		return nil, ErrRevertMulDiv
+	}
+	return result, nil
+}
+
+// MulDivRoundingUpStrict is MulDivRoundingUp reverting like FullMath.mulDivCeiling
+func MulDivRoundingUpStrict(a, b, denominator *big.Int) (*big.Int, error) {
+	result, err := MulDivStrict(a, b, denominator)
+	if err != nil {
+		return nil, err
+	}
+	if new(big.Int).Rem(new(big.Int).Mul(a, b), denominator).Sign() != 0 {
+		if result.Cmp(entities.MaxUint256) == 0 {
+			return nil, ErrRevertMulDivCeiling
+		}
+		result.Add(result, constants.One)
+	}
+	return result, nil
+}
+
+// ApplyLiquidityDeltaStrict is ApplyLiquidityDelta on uint128 liquidities, the delta being cast with SafeCast.toUint128
+func ApplyLiquidityDeltaStrict(liquidity, liquidityDelta *big.Int, isAddLiquidity bool) (*big.Int, error) {
+	if !fitsUint(liquidityDelta, MaxUint128) {
+		return nil, ErrRevertSafeCastUint128
+	}
+	if !fitsUint(liquidity, MaxUint128) {
+		return nil, ErrRevertLiquidityDelta
+	}
+	result := ApplyLiquidityDelta(liquidity, liquidityDelta, isAddLiquidity)
+	if !fitsUint(result, MaxUint128) {
+		return nil, ErrRevertLiquidityDelta
+	}
+	return result, nil
+}
+
+// GetAmount0DeltaStrict is GetAmount0Delta on uint160 prices and a uint128 liquidity, reverting like the contract
+func GetAmount0DeltaStrict(sqrtRatioAX96, sqrtRatioBX96, liquidity *big.Int, roundUp bool) (*big.Int, error) {
+	if !fitsUint(sqrtRatioAX96, MaxUint160) || !fitsUint(sqrtRatioBX96, MaxUint160) || !fitsUint(liquidity, MaxUint128) {
+		return nil, ErrRevertArithmetic
+	}
+	if sqrtRatioAX96.Cmp(sqrtRatioBX96) >= 0 {
+		sqrtRatioAX96, sqrtRatioBX96 = sqrtRatioBX96, sqrtRatioAX96
+	}
+	if sqrtRatioAX96.Sign() == 0 {
+		return nil, ErrRevertAmount0Delta
+	}
+
+	numerator1 := new(big.Int).Lsh(liquidity, 96)
+	numerator2 := new(big.Int).Sub(sqrtRatioBX96, sqrtRatioAX96)
+	if roundUp {
+		amount, err := MulDivRoundingUpStrict(numerator1, numerator2, sqrtRatioBX96)
+		if err != nil {
+			return nil, err
+		}
+		return MulDivRoundingUp(amount, constants.One, sqrtRatioAX96), nil
+	}
+	amount, err := MulDivStrict(numerator1, numerator2, sqrtRatioBX96)
+	if err != nil {
+		return nil, err
+	}
+	return amount.Div(amount, sqrtRatioAX96), nil
+}
+
+// GetAmount1DeltaStrict is GetAmount1Delta on uint160 prices and a uint128 liquidity, reverting like the contract
+func GetAmount1DeltaStrict(sqrtRatioAX96, sqrtRatioBX96, liquidity *big.Int, roundUp bool) (*big.Int, error) {
+	if !fitsUint(sqrtRatioAX96, MaxUint160) || !fitsUint(sqrtRatioBX96, MaxUint160) || !fitsUint(liquidity, MaxUint128) {
+		return nil, ErrRevertArithmetic
+	}
+	if sqrtRatioAX96.Cmp(sqrtRatioBX96) >= 0 {
+		sqrtRatioAX96, sqrtRatioBX96 = sqrtRatioBX96, sqrtRatioAX96
+	}
+	if roundUp {
+		return MulDivRoundingUpStrict(liquidity, new(big.Int).Sub(sqrtRatioBX96, sqrtRatioAX96), constants.Q96)
+	}
+	return MulDivStrict(liquidity, new(big.Int).Sub(sqrtRatioBX96, sqrtRatioAX96), constants.Q96)
+}
+
+// GetNextSqrtPriceFromInputStrict is GetNextSqrtPriceFromInput reverting where the contract's checked math does
+func GetNextSqrtPriceFromInputStrict(sqrtPX96, liquidity, amountIn *big.Int, zeroForOne bool) (*big.Int, error) {
+	if err := checkNextSqrtPriceInputs(sqrtPX96, liquidity, amountIn); err != nil {
+		return nil, err
+	}
+	if zeroForOne {
+		return getNextSqrtPriceFromAmount0RoundingUpStrict(sqrtPX96, liquidity, amountIn, true)
+	}
+	return getNextSqrtPriceFromAmount1RoundingDownStrict(sqrtPX96, liquidity, amountIn, true)
+}
+
+// GetNextSqrtPriceFromOutputStrict is GetNextSqrtPriceFromOutput reverting where the contract's checked math does
+func GetNextSqrtPriceFromOutputStrict(sqrtPX96, liquidity, amountOut *big.Int, zeroForOne bool) (*big.Int, error) {
+	if err := checkNextSqrtPriceInputs(sqrtPX96, liquidity, amountOut); err != nil {
+		return nil, err
+	}
+	if zeroForOne {
+		return getNextSqrtPriceFromAmount1RoundingDownStrict(sqrtPX96, liquidity, amountOut, false)
+	}
+	return getNextSqrtPriceFromAmount0RoundingUpStrict(sqrtPX96, liquidity, amountOut, false)
+}
+
+func checkNextSqrtPriceInputs(sqrtPX96, liquidity, amount *big.Int) error {
+	if sqrtPX96.Sign() <= 0 {
+		return ErrSqrtPriceLessThanZero
+	}
+	if liquidity.Sign() <= 0 {
+		return ErrLiquidityLessThanZero
+	}
+	if !fitsUint(sqrtPX96, MaxUint160) || !fitsUint(liquidity, MaxUint128) || !fitsUint(amount, entities.MaxUint256) {
+		return ErrRevertArithmetic
+	}
+	return nil
+}
+
+func getNextSqrtPriceFromAmount0RoundingUpStrict(sqrtPX96, liquidity, amount *big.Int, add bool) (*big.Int, error) {
+	if amount.Sign() == 0 {
+		return sqrtPX96, nil
+	}
+	if add {
+		// the contract falls back to a formula that cannot overflow its multiplication, but can its addition
+		numerator1 := new(big.Int).Lsh(liquidity, 96)
+		product := new(big.Int).Mul(amount, sqrtPX96)
+		if product.Cmp(entities.MaxUint256) > 0 || new(big.Int).Add(numerator1, product).Cmp(entities.MaxUint256) > 0 {
+			if new(big.Int).Add(new(big.Int).Div(numerator1, sqrtPX96), amount).Cmp(entities.MaxUint256) > 0 {
+				return nil, ErrRevertSafeMathAdd
+			}
+		}
+	}
+	next, err := getNextSqrtPriceFromAmount0RoundingUp(sqrtPX96, liquidity, amount, add)
+	if err != nil {
+		return nil, err
+	}
+	if next.Cmp(entities.MaxUint256) > 0 {
+		return nil, ErrRevertMulDiv
+	}
+	if next.Cmp(MaxUint160) > 0 {
+		return nil, ErrRevertSafeCastUint160
+	}
+	return next, nil
+}
+
+func getNextSqrtPriceFromAmount1RoundingDownStrict(sqrtPX96, liquidity, amount *big.Int, add bool) (*big.Int, error) {
+	if add {
+		quotient, err := MulDivStrict(amount, constants.Q96, liquidity)
+		if err != nil {
+			return nil, err
+		}
+		next := quotient.Add(quotient, sqrtPX96)
+		if next.Cmp(entities.MaxUint256) > 0 {
+			return nil, ErrRevertSafeMathAdd
+		}
+		if next.Cmp(MaxUint160) > 0 {
+			return nil, ErrRevertSafeCastUint160
+		}
+		return next, nil
+	}
+	if _, err := MulDivRoundingUpStrict(amount, constants.Q96, liquidity); err != nil {
+		return nil, err
+	}
+	return getNextSqrtPriceFromAmount1RoundingDown(sqrtPX96, liquidity, amount, false)
+}
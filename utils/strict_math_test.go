@@ -0,0 +1,129 @@
+package utils
+
+import (
+	"errors"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/daoleno/uniswap-sdk-core/entities"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+)
+
+func TestMulDivStrict(t *testing.T) {
+	result, err := MulDivStrict(entities.MaxUint256, entities.MaxUint256, entities.MaxUint256)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.MaxUint256, result)
+
+	_, err = MulDivStrict(big.NewInt(1), big.NewInt(1), big.NewInt(0))
+	assert.ErrorIs(t, err, ErrRevertMulDiv)
+	_, err = MulDivStrict(entities.MaxUint256, big.NewInt(2), big.NewInt(1))
+	assert.ErrorIs(t, err, ErrRevertMulDiv)
+	_, err = MulDivStrict(big.NewInt(-1), big.NewInt(1), big.NewInt(1))
+	assert.ErrorIs(t, err, ErrRevertArithmetic)
+
+	// (2^192 - 1) * (2^192 + 1) / 2^128 rounds down to 2^256 - 1 with a remainder
+	a := new(big.Int).Sub(new(big.Int).Lsh(constants.One, 192), constants.One)
+	b := new(big.Int).Add(new(big.Int).Lsh(constants.One, 192), constants.One)
+	d := new(big.Int).Lsh(constants.One, 128)
+	result, err = MulDivStrict(a, b, d)
+	assert.NoError(t, err)
+	assert.Equal(t, entities.MaxUint256, result)
+	_, err = MulDivRoundingUpStrict(a, b, d)
+	assert.ErrorIs(t, err, ErrRevertMulDivCeiling)
+
+	result, err = MulDivRoundingUpStrict(big.NewInt(7), big.NewInt(3), big.NewInt(2))
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(11), result)
+}
+
+func TestApplyLiquidityDeltaStrict(t *testing.T) {
+	result, err := ApplyLiquidityDeltaStrict(big.NewInt(10), big.NewInt(3), false)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(7), result)
+
+	_, err = ApplyLiquidityDeltaStrict(big.NewInt(3), big.NewInt(10), false)
+	assert.ErrorIs(t, err, ErrRevertLiquidityDelta)
+	_, err = ApplyLiquidityDeltaStrict(MaxUint128, big.NewInt(1), true)
+	assert.ErrorIs(t, err, ErrRevertLiquidityDelta)
+	_, err = ApplyLiquidityDeltaStrict(big.NewInt(1), new(big.Int).Add(MaxUint128, constants.One), true)
+	assert.ErrorIs(t, err, ErrRevertSafeCastUint128)
+
+	var revert *RevertError
+	assert.True(t, errors.As(err, &revert))
+	assert.Equal(t, "SafeCast.toUint128: execution reverted", err.Error())
+	assert.Equal(t, "LiqDeltaMath.applyLiquidityDelta: panic 0x11", ErrRevertLiquidityDelta.Error())
+}
+
+func TestSqrtPriceMathStrict(t *testing.T) {
+	r := rand.New(rand.NewSource(6))
+	for i := 0; i < 2000; i++ {
+		sqrtA, _ := GetSqrtRatioAtTick(MinTick + r.Intn(MaxTick-MinTick))
+		sqrtB, _ := GetSqrtRatioAtTick(MinTick + r.Intn(MaxTick-MinTick))
+		liquidity := new(big.Int).Add(randomBits(r, r.Intn(120)), constants.One)
+		roundUp := r.Intn(2) == 0
+
+		amount0, err := GetAmount0DeltaStrict(sqrtA, sqrtB, liquidity, roundUp)
+		if err == nil {
+			assert.Equal(t, 0, GetAmount0Delta(sqrtA, sqrtB, liquidity, roundUp).Cmp(amount0))
+		} else {
+			assert.True(t, GetAmount0Delta(sqrtA, sqrtB, liquidity, roundUp).Cmp(entities.MaxUint256) > 0)
+		}
+		amount1, err := GetAmount1DeltaStrict(sqrtA, sqrtB, liquidity, roundUp)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, GetAmount1Delta(sqrtA, sqrtB, liquidity, roundUp).Cmp(amount1))
+
+		amount := randomBits(r, 1+r.Intn(100))
+		zeroForOne := r.Intn(2) == 0
+		next, err := GetNextSqrtPriceFromInputStrict(sqrtA, liquidity, amount, zeroForOne)
+		if err == nil {
+			expected, _ := GetNextSqrtPriceFromInput(sqrtA, liquidity, amount, zeroForOne)
+			assert.Equal(t, 0, expected.Cmp(next))
+		} else {
+			assert.ErrorIs(t, err, ErrRevertSafeCastUint160)
+		}
+	}
+
+	_, err := GetAmount0DeltaStrict(MaxSqrtRatio, MinSqrtRatio, new(big.Int).Lsh(constants.One, 128), true)
+	assert.ErrorIs(t, err, ErrRevertArithmetic)
+
+	// the price cannot go past 2^160
+	_, err = GetNextSqrtPriceFromInputStrict(MaxSqrtRatio, big.NewInt(1), new(big.Int).Lsh(constants.One, 100), false)
+	assert.ErrorIs(t, err, ErrRevertSafeCastUint160)
+	_, err = GetNextSqrtPriceFromOutputStrict(MaxSqrtRatio, big.NewInt(1), MaxSqrtRatio, true)
+	assert.ErrorIs(t, err, ErrInvariant)
+}
+
+func TestMathStrictComputeSwapStep(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 5000; i++ {
+		c := randomSwapStep(r)
+		used, returned, deltaL, next, err := MathStrict.ComputeSwapStep(c.liquidity, c.currentSqrtP, c.targetSqrtP, c.fee, c.specifiedAmount, c.isExactInput, c.isToken0)
+		if err != nil {
+			var revert *RevertError
+			assert.True(t, errors.As(err, &revert), "revert of %+v", c)
+			continue
+		}
+		expectedUsed, expectedReturned, expectedDeltaL, expectedNext, _ := ComputeSwapStep(c.liquidity, c.currentSqrtP, c.targetSqrtP, c.fee, c.specifiedAmount, c.isExactInput, c.isToken0)
+		assert.Equal(t, 0, expectedUsed.Cmp(used))
+		assert.Equal(t, 0, expectedReturned.Cmp(returned))
+		assert.Equal(t, 0, expectedDeltaL.Cmp(deltaL))
+		assert.Equal(t, 0, expectedNext.Cmp(next))
+	}
+
+	current := new(big.Int).Lsh(constants.One, 96)
+	target := new(big.Int).Sub(current, big.NewInt(1000))
+	_, _, _, _, err := MathStrict.ComputeSwapStep(big.NewInt(1e18), current, target, constants.Fee1, new(big.Int).Lsh(constants.One, 300), true, true)
+	assert.ErrorIs(t, err, ErrRevertArithmetic)
+	_, _, _, _, err = MathStrict.ComputeSwapStep(new(big.Int).Lsh(constants.One, 200), current, target, constants.Fee1, big.NewInt(1), true, true)
+	assert.ErrorIs(t, err, ErrRevertArithmetic)
+
+	// the big.Int math keeps going where the contract reverts
+	_, _, _, _, err = MathBigInt.ComputeSwapStep(new(big.Int).Lsh(constants.One, 159), current, target, constants.Fee1, new(big.Int).Neg(MaxInt256), false, true)
+	assert.NoError(t, err)
+	_, _, _, _, err = MathStrict.ComputeSwapStep(new(big.Int).Lsh(constants.One, 159), current, target, constants.Fee1, new(big.Int).Neg(MaxInt256), false, true)
+	var revert *RevertError
+	assert.True(t, errors.As(err, &revert))
+}
@@ -0,0 +1,398 @@
+package utils
+
+import (
+	"github.com/holiman/uint256"
+
+	"github.com/KyberNetwork/elastic-go-sdk/v2/constants"
+)
+
+/**
+ * ComputeSwapStepU256 is ComputeSwapStep on 256-bit integers. The specified, used and returned amounts are two's
+ * complement int256 values, see I256FromBig and I256ToBig.
+ *
+ * ErrUint256Overflow is returned whenever an intermediate value would overflow or go negative, which the contract
+ * only allows for inputs it never produces; ComputeSwapStep must be used for such inputs to get the same results.
+ */
+func ComputeSwapStepU256(
+	liquidity *uint256.Int,
+	currentSqrtP *uint256.Int,
+	targetSqrtP *uint256.Int,
+	feeInFeeUnits constants.FeeAmount,
+	specifiedAmount *uint256.Int,
+	isExactInput bool,
+	isToken0 bool,
+) (
+	usedAmount *uint256.Int,
+	returnedAmount *uint256.Int,
+	deltaL *uint256.Int,
+	nextSqrtP *uint256.Int,
+	err error,
+) {
+	if currentSqrtP.Eq(targetSqrtP) {
+		return currentSqrtP, new(uint256.Int), new(uint256.Int), new(uint256.Int), nil
+	}
+	fee := uint256.NewInt(uint64(feeInFeeUnits))
+	if fee.Gt(u256FeeUnits) {
+		return nil, nil, nil, nil, ErrUint256Overflow
+	}
+
+	usedAmount, err = calcReachAmountU256(liquidity, currentSqrtP, targetSqrtP, fee, isExactInput, isToken0)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	if isExactInput && usedAmount.Sgt(specifiedAmount) || (!isExactInput && !usedAmount.Sgt(specifiedAmount)) {
+		usedAmount = specifiedAmount
+	} else {
+		nextSqrtP = targetSqrtP
+	}
+
+	absDelta := new(uint256.Int).Abs(usedAmount)
+	if absDelta.Gt(u256MaxInt256) {
+		return nil, nil, nil, nil, ErrUint256Overflow
+	}
+
+	if nextSqrtP == nil || nextSqrtP.IsZero() {
+		deltaL, err = estimateIncrementalLiquidityU256(absDelta, liquidity, currentSqrtP, fee, isExactInput, isToken0)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		nextSqrtP, err = calcFinalPriceU256(absDelta, liquidity, deltaL, currentSqrtP, isExactInput, isToken0)
+	} else {
+		deltaL, err = calcIncrementalLiquidityU256(absDelta, liquidity, currentSqrtP, nextSqrtP, isExactInput, isToken0)
+	}
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	returnedAmount, err = calcReturnedAmountU256(liquidity, currentSqrtP, nextSqrtP, deltaL, isExactInput, isToken0)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+	return usedAmount, returnedAmount, deltaL, nextSqrtP, nil
+}
+
+// calcReachAmountU256 returns the int256 amount needed to reach targetSqrtP from currentSqrtP
+func calcReachAmountU256(
+	liquidity, currentSqrtP, targetSqrtP, fee *uint256.Int,
+	isExactInput bool,
+	isToken0 bool,
+) (*uint256.Int, error) {
+	absPriceDiff := new(uint256.Int)
+	if currentSqrtP.Lt(targetSqrtP) {
+		absPriceDiff.Sub(targetSqrtP, currentSqrtP)
+	} else {
+		absPriceDiff.Sub(currentSqrtP, targetSqrtP)
+	}
+
+	// the sqrt prices fit in 160 bits, so their products with the fee units fit in 256 bits
+	if currentSqrtP.BitLen() > 160 || targetSqrtP.BitLen() > 160 || liquidity.BitLen() > 160 {
+		return nil, ErrUint256Overflow
+	}
+	twoFeeDiff := new(uint256.Int).Mul(u256TwoFeeUnits, absPriceDiff)
+
+	var reachAmount *uint256.Int
+	if isExactInput {
+		if isToken0 {
+			denominator, err := subU256(new(uint256.Int).Mul(u256TwoFeeUnits, targetSqrtP), new(uint256.Int).Mul(fee, currentSqrtP))
+			if err != nil {
+				return nil, err
+			}
+			numerator, err := MulDivU256(liquidity, twoFeeDiff, denominator)
+			if err != nil {
+				return nil, err
+			}
+			if reachAmount, err = MulDivU256(numerator, u256Q96, currentSqrtP); err != nil {
+				return nil, err
+			}
+		} else {
+			denominator, err := subU256(new(uint256.Int).Mul(u256TwoFeeUnits, currentSqrtP), new(uint256.Int).Mul(fee, targetSqrtP))
+			if err != nil {
+				return nil, err
+			}
+			numerator, err := MulDivU256(liquidity, twoFeeDiff, denominator)
+			if err != nil {
+				return nil, err
+			}
+			if reachAmount, err = MulDivU256(numerator, currentSqrtP, u256Q96); err != nil {
+				return nil, err
+			}
+		}
+		if reachAmount.Gt(u256MaxInt256) {
+			return nil, errInt256Overflow
+		}
+		return reachAmount, nil
+	}
+
+	if isToken0 {
+		denominator, err := subU256(new(uint256.Int).Mul(u256TwoFeeUnits, currentSqrtP), new(uint256.Int).Mul(fee, targetSqrtP))
+		if err != nil {
+			return nil, err
+		}
+		numerator, err := subU256(denominator, new(uint256.Int).Mul(fee, currentSqrtP))
+		if err != nil {
+			return nil, err
+		}
+		if numerator, err = MulDivU256(new(uint256.Int).Lsh(liquidity, 96), numerator, denominator); err != nil {
+			return nil, err
+		}
+		if reachAmount, err = MulDivU256(numerator, absPriceDiff, currentSqrtP); err != nil {
+			return nil, err
+		}
+		if targetSqrtP.IsZero() {
+			return nil, ErrUint256Overflow
+		}
+		reachAmount.Div(reachAmount, targetSqrtP)
+	} else {
+		denominator, err := subU256(new(uint256.Int).Mul(u256TwoFeeUnits, targetSqrtP), new(uint256.Int).Mul(fee, currentSqrtP))
+		if err != nil {
+			return nil, err
+		}
+		numerator, err := subU256(denominator, new(uint256.Int).Mul(fee, targetSqrtP))
+		if err != nil {
+			return nil, err
+		}
+		if numerator, err = MulDivU256(liquidity, numerator, denominator); err != nil {
+			return nil, err
+		}
+		if reachAmount, err = MulDivU256(numerator, absPriceDiff, u256Q96); err != nil {
+			return nil, err
+		}
+	}
+	if reachAmount.Gt(u256MaxInt256) {
+		return nil, errInt256Overflow
+	}
+	return reachAmount.Neg(reachAmount), nil
+}
+
+// estimateIncrementalLiquidityU256 estimates deltaL for the final swap step, where the next tick is not crossed
+func estimateIncrementalLiquidityU256(
+	absDelta, liquidity, currentSqrtP, fee *uint256.Int,
+	isExactInput bool,
+	isToken0 bool,
+) (*uint256.Int, error) {
+	feeDelta, err := mulU256(absDelta, fee)
+	if err != nil {
+		return nil, err
+	}
+	if isExactInput {
+		if isToken0 {
+			return MulDivU256(currentSqrtP, feeDelta, new(uint256.Int).Lsh(u256TwoFeeUnits, 96))
+		}
+		denominator, err := mulU256(u256TwoFeeUnits, currentSqrtP)
+		if err != nil {
+			return nil, err
+		}
+		return MulDivU256(u256Q96, feeDelta, denominator)
+	}
+
+	// the smaller root of fee * x^2 - 2 * b * x + c = 0
+	if liquidity.BitLen() > 200 {
+		return nil, ErrUint256Overflow
+	}
+	b := new(uint256.Int).Mul(new(uint256.Int).Sub(u256FeeUnits, fee), liquidity)
+	c, err := mulU256(new(uint256.Int).Mul(fee, liquidity), absDelta)
+	if err != nil {
+		return nil, err
+	}
+	feeUnitsDelta, err := mulU256(u256FeeUnits, absDelta)
+	if err != nil {
+		return nil, err
+	}
+
+	var bDelta *uint256.Int
+	if isToken0 {
+		if bDelta, err = MulDivU256(feeUnitsDelta, currentSqrtP, u256Q96); err != nil {
+			return nil, err
+		}
+		if c, err = MulDivU256(c, currentSqrtP, u256Q96); err != nil {
+			return nil, err
+		}
+	} else {
+		if bDelta, err = MulDivU256(feeUnitsDelta, u256Q96, currentSqrtP); err != nil {
+			return nil, err
+		}
+		if c, err = MulDivU256(c, u256Q96, currentSqrtP); err != nil {
+			return nil, err
+		}
+	}
+	if b, err = subU256(b, bDelta); err != nil {
+		return nil, err
+	}
+	return GetSmallerRootOfQuadEqnU256(fee, b, c)
+}
+
+// calcIncrementalLiquidityU256 calculates deltaL for an intermediate swap step, where the next tick is crossed
+func calcIncrementalLiquidityU256(
+	absDelta, liquidity, currentSqrtP, nextSqrtP *uint256.Int,
+	isExactInput bool,
+	isToken0 bool,
+) (*uint256.Int, error) {
+	var (
+		tmp1, tmp2, tmp3 *uint256.Int
+		err              error
+	)
+	if isToken0 {
+		tmp1, err = MulDivU256(liquidity, u256Q96, currentSqrtP)
+	} else {
+		tmp1, err = MulDivU256(liquidity, currentSqrtP, u256Q96)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if isExactInput {
+		tmp2, err = addU256(tmp1, absDelta)
+	} else {
+		tmp2, err = subU256(tmp1, absDelta)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if isToken0 {
+		tmp3, err = MulDivU256(nextSqrtP, tmp2, u256Q96)
+	} else {
+		tmp3, err = MulDivU256(tmp2, u256Q96, nextSqrtP)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// in edge cases where liquidity or absDelta is small, tmp3 might be below liquidity due to rounding
+	if tmp3.Gt(liquidity) {
+		return tmp3.Sub(tmp3, liquidity), nil
+	}
+	return new(uint256.Int), nil
+}
+
+// calcFinalPriceU256 calculates the sqrt price reached by the final swap step
+func calcFinalPriceU256(
+	absDelta, liquidity, deltaL, currentSqrtP *uint256.Int,
+	isExactInput bool,
+	isToken0 bool,
+) (*uint256.Int, error) {
+	var finalPrice *uint256.Int
+	liquidityAfter, err := addU256(liquidity, deltaL)
+	if err != nil {
+		return nil, err
+	}
+
+	if isToken0 {
+		tmp, err := MulDivU256(absDelta, currentSqrtP, u256Q96)
+		if err != nil {
+			return nil, err
+		}
+		if isExactInput {
+			denominator, err := addU256(liquidity, tmp)
+			if err != nil {
+				return nil, err
+			}
+			finalPrice, err = MulDivRoundingUpU256(liquidityAfter, currentSqrtP, denominator)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			denominator, err := subU256(liquidity, tmp)
+			if err != nil {
+				return nil, err
+			}
+			finalPrice, err = MulDivU256(liquidityAfter, currentSqrtP, denominator)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		tmp, err := MulDivU256(absDelta, u256Q96, currentSqrtP)
+		if err != nil {
+			return nil, err
+		}
+		if isExactInput {
+			numerator, err := addU256(liquidity, tmp)
+			if err != nil {
+				return nil, err
+			}
+			finalPrice, err = MulDivU256(numerator, currentSqrtP, liquidityAfter)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			numerator, err := subU256(liquidity, tmp)
+			if err != nil {
+				return nil, err
+			}
+			finalPrice, err = MulDivRoundingUpU256(numerator, currentSqrtP, liquidityAfter)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if isExactInput && finalPrice.Eq(u256One) {
+		finalPrice.Clear()
+	}
+	return finalPrice, nil
+}
+
+// calcReturnedAmountU256 calculates the int256 amount returned in exchange for the used amount
+func calcReturnedAmountU256(
+	liquidity, currentSqrtP, nextSqrtP, deltaL *uint256.Int,
+	isExactInput bool,
+	isToken0 bool,
+) (*uint256.Int, error) {
+	var returnedAmount *uint256.Int
+	if isToken0 {
+		fee, err := MulDivRoundingUpU256(deltaL, nextSqrtP, u256Q96)
+		if err != nil {
+			return nil, err
+		}
+		if isExactInput {
+			diff, err := subU256(currentSqrtP, nextSqrtP)
+			if err != nil {
+				return nil, err
+			}
+			amount, err := MulDivU256(liquidity, diff, u256Q96)
+			if err != nil {
+				return nil, err
+			}
+			if returnedAmount, err = subI256(fee, amount); err != nil {
+				return nil, err
+			}
+		} else {
+			diff, err := subU256(nextSqrtP, currentSqrtP)
+			if err != nil {
+				return nil, err
+			}
+			amount, err := MulDivRoundingUpU256(liquidity, diff, u256Q96)
+			if err != nil {
+				return nil, err
+			}
+			if returnedAmount, err = addU256(fee, amount); err != nil {
+				return nil, err
+			}
+			if returnedAmount.Gt(u256MaxInt256) {
+				return nil, ErrUint256Overflow
+			}
+		}
+	} else {
+		liquidityAfter, err := addU256(liquidity, deltaL)
+		if err != nil {
+			return nil, err
+		}
+		amountAfter, err := MulDivRoundingUpU256(liquidityAfter, u256Q96, nextSqrtP)
+		if err != nil {
+			return nil, err
+		}
+		amountBefore, err := MulDivRoundingUpU256(liquidity, u256Q96, currentSqrtP)
+		if err != nil {
+			return nil, err
+		}
+		if returnedAmount, err = subI256(amountAfter, amountBefore); err != nil {
+			return nil, err
+		}
+	}
+
+	if isExactInput && returnedAmount.Eq(u256One) {
+		// rounding make returnedAmount == 1
+		returnedAmount.Clear()
+	}
+	return returnedAmount, nil
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"github.com/holiman/uint256"
+)
+
+var (
+	u256MaxUint256    = new(uint256.Int).SetAllOne()
+	u256MinSqrtRatio  = uint256.MustFromBig(MinSqrtRatio)
+	u256MaxSqrtRatio  = uint256.MustFromBig(MaxSqrtRatio)
+	u256SqrtConsts    [21]*uint256.Int
+	u256MagicSqrt     = uint256.MustFromBig(magicSqrt10001)
+	u256MagicTickLow  = uint256.MustFromBig(magicTickLow)
+	u256MagicTickHigh = uint256.MustFromBig(magicTickHigh)
+)
+
+func init() {
+	for i, c := range []*uint256.Int{
+		uint256.MustFromBig(sqrtConst1), uint256.MustFromBig(sqrtConst2), uint256.MustFromBig(sqrtConst3),
+		uint256.MustFromBig(sqrtConst4), uint256.MustFromBig(sqrtConst5), uint256.MustFromBig(sqrtConst6),
+		uint256.MustFromBig(sqrtConst7), uint256.MustFromBig(sqrtConst8), uint256.MustFromBig(sqrtConst9),
+		uint256.MustFromBig(sqrtConst10), uint256.MustFromBig(sqrtConst11), uint256.MustFromBig(sqrtConst12),
+		uint256.MustFromBig(sqrtConst13), uint256.MustFromBig(sqrtConst14), uint256.MustFromBig(sqrtConst15),
+		uint256.MustFromBig(sqrtConst16), uint256.MustFromBig(sqrtConst17), uint256.MustFromBig(sqrtConst18),
+		uint256.MustFromBig(sqrtConst19), uint256.MustFromBig(sqrtConst20), uint256.MustFromBig(sqrtConst21),
+	} {
+		u256SqrtConsts[i] = c
+	}
+}
+
+// GetSqrtRatioAtTickU256 is GetSqrtRatioAtTick on 256-bit integers
+func GetSqrtRatioAtTickU256(tick int) (*uint256.Int, error) {
+	if tick < MinTick || tick > MaxTick {
+		return nil, ErrInvalidTick
+	}
+	absTick := tick
+	if tick < 0 {
+		absTick = -tick
+	}
+	ratio := new(uint256.Int)
+	if absTick&0x1 != 0 {
+		ratio.Set(u256SqrtConsts[0])
+	} else {
+		ratio.Set(u256SqrtConsts[1])
+	}
+	// the ratio and the constants are below 2^128, so the products fit in 256 bits
+	for i := 1; i < 20; i++ {
+		if absTick&(1<<i) != 0 {
+			ratio.Rsh(ratio.Mul(ratio, u256SqrtConsts[i+1]), 128)
+		}
+	}
+	if tick > 0 {
+		ratio.Div(u256MaxUint256, ratio)
+	}
+
+	// back to Q96, rounding up
+	rounded := !new(uint256.Int).And(ratio, uint256.NewInt(0xffffffff)).IsZero()
+	ratio.Rsh(ratio, 32)
+	if rounded {
+		ratio.AddUint64(ratio, 1)
+	}
+	return ratio, nil
+}
+
+// GetTickAtSqrtRatioU256 is GetTickAtSqrtRatio on 256-bit integers, the log is computed as an int256
+func GetTickAtSqrtRatioU256(sqrtRatioX96 *uint256.Int) (int, error) {
+	if sqrtRatioX96.Lt(u256MinSqrtRatio) || !sqrtRatioX96.Lt(u256MaxSqrtRatio) {
+		return 0, ErrInvalidSqrtRatio
+	}
+	sqrtRatioX128 := new(uint256.Int).Lsh(sqrtRatioX96, 32)
+	msb := sqrtRatioX128.BitLen() - 1
+
+	r := new(uint256.Int)
+	if msb >= 128 {
+		r.Rsh(sqrtRatioX128, uint(msb-127))
+	} else {
+		r.Lsh(sqrtRatioX128, uint(127-msb))
+	}
+
+	log2 := new(uint256.Int).SetUint64(uint64(msb - 128))
+	if msb < 128 {
+		log2.Neg(uint256.NewInt(uint64(128 - msb)))
+	}
+	log2.Lsh(log2, 64)
+
+	f := new(uint256.Int)
+	for i := 0; i < 14; i++ {
+		r.Rsh(r.Mul(r, r), 127)
+		f.Rsh(r, 128)
+		log2.Or(log2, new(uint256.Int).Lsh(f, uint(63-i)))
+		r.Rsh(r, uint(f.Uint64()))
+	}
+
+	logSqrt10001 := new(uint256.Int).Mul(log2, u256MagicSqrt)
+
+	tickLow := int(int64(new(uint256.Int).SRsh(new(uint256.Int).Sub(logSqrt10001, u256MagicTickLow), 128).Uint64()))
+	tickHigh := int(int64(new(uint256.Int).SRsh(new(uint256.Int).Add(logSqrt10001, u256MagicTickHigh), 128).Uint64()))
+
+	if tickLow == tickHigh {
+		return tickLow, nil
+	}
+
+	sqrtRatio, err := GetSqrtRatioAtTickU256(tickHigh)
+	if err != nil {
+		return 0, err
+	}
+	if !sqrtRatio.Gt(sqrtRatioX96) {
+		return tickHigh, nil
+	}
+	return tickLow, nil
+}
@@ -0,0 +1,167 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+var (
+	ErrMissingChainID  = errors.New("chain id is required")
+	ErrMissingGasPrice = errors.New("either a gas price or fee caps are required")
+	ErrGasPriceAndCaps = errors.New("gas price and fee caps are mutually exclusive")
+	ErrNoDeployment    = errors.New("no deployment for chain")
+)
+
+// The addresses of the contracts of a deployment on one chain
+type Deployment struct {
+	ChainID         uint64         `json:"chainId"`
+	Factory         common.Address `json:"factory"`
+	Router          common.Address `json:"router"`
+	PositionManager common.Address `json:"positionManager"`
+	Quoter          common.Address `json:"quoter"`
+	TicksFeesReader common.Address `json:"ticksFeesReader"`
+	Multicall       common.Address `json:"multicall"`
+}
+
+// The deployments of every chain, by chain id
+type Deployments map[uint64]*Deployment
+
+/**
+ * Parses a deployment config
+ * @param data a JSON array of deployments
+ */
+func ParseDeployments(data []byte) (Deployments, error) {
+	var list []*Deployment
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, err
+	}
+	deployments := make(Deployments, len(list))
+	for _, d := range list {
+		deployments[d.ChainID] = d
+	}
+	return deployments, nil
+}
+
+// Get returns the deployment of the given chain
+func (d Deployments) Get(chainID uint64) (*Deployment, error) {
+	deployment, ok := d[chainID]
+	if !ok {
+		return nil, ErrNoDeployment
+	}
+	return deployment, nil
+}
+
+// The fields of a transaction that are not part of the method parameters
+type TxOptions struct {
+	ChainID   *big.Int
+	Nonce     uint64
+	GasLimit  uint64
+	GasPrice  *big.Int // Set for a legacy transaction
+	GasTipCap *big.Int // Set along with GasFeeCap for an EIP-1559 transaction, zero if nil
+	GasFeeCap *big.Int
+}
+
+/**
+ * Builds the transaction executing the method parameters on a contract. An EIP-1559 transaction is built when a fee
+ * cap is set and a legacy one otherwise. The pre calls of the parameters must be sent in their own transactions before.
+ * @param to the contract, e.g. the router of a Deployment
+ * @param params the calldata and value to send
+ * @param opts the nonce, gas limit and fees
+ */
+func BuildTransaction(to common.Address, params *MethodParameters, opts *TxOptions) (*types.Transaction, error) {
+	value := params.Value
+	if value == nil {
+		value = big.NewInt(0)
+	}
+	if opts.GasFeeCap != nil {
+		if opts.GasPrice != nil {
+			return nil, ErrGasPriceAndCaps
+		}
+		if opts.ChainID == nil {
+			return nil, ErrMissingChainID
+		}
+		tip := opts.GasTipCap
+		if tip == nil {
+			tip = big.NewInt(0)
+		}
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   opts.ChainID,
+			Nonce:     opts.Nonce,
+			GasTipCap: tip,
+			GasFeeCap: opts.GasFeeCap,
+			Gas:       opts.GasLimit,
+			To:        &to,
+			Value:     value,
+			Data:      params.Calldata,
+		}), nil
+	}
+	if opts.GasPrice == nil {
+		return nil, ErrMissingGasPrice
+	}
+	return types.NewTx(&types.LegacyTx{
+		Nonce:    opts.Nonce,
+		GasPrice: opts.GasPrice,
+		Gas:      opts.GasLimit,
+		To:       &to,
+		Value:    value,
+		Data:     params.Calldata,
+	}), nil
+}
+
+// SignTransaction signs a transaction for the given chain, legacy transactions are signed with EIP-155 replay protection
+func SignTransaction(tx *types.Transaction, chainID *big.Int, key *ecdsa.PrivateKey) (*types.Transaction, error) {
+	if chainID == nil {
+		return nil, ErrMissingChainID
+	}
+	return types.SignTx(tx, types.LatestSignerForChainID(chainID), key)
+}
+
+// A JSON-RPC request
+type RPCRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// The transaction object of an eth_call
+type CallArgs struct {
+	From  *common.Address `json:"from,omitempty"`
+	To    common.Address  `json:"to"`
+	Data  hexutil.Bytes   `json:"data"`
+	Value *hexutil.Big    `json:"value,omitempty"`
+}
+
+/**
+ * Builds the eth_call request simulating the method parameters
+ * @param id the request id
+ * @param from the sender, or nil
+ * @param to the contract called
+ * @param params the calldata and value to send
+ * @param block the block tag or hex number to call at, "latest" if empty
+ */
+func EthCallRequest(id uint64, from *common.Address, to common.Address, params *MethodParameters, block string) *RPCRequest {
+	if block == "" {
+		block = "latest"
+	}
+	args := CallArgs{From: from, To: to, Data: params.Calldata}
+	if params.Value != nil && params.Value.Sign() > 0 {
+		args.Value = (*hexutil.Big)(params.Value)
+	}
+	return &RPCRequest{JSONRPC: "2.0", ID: id, Method: "eth_call", Params: []interface{}{args, block}}
+}
+
+// SendRawTransactionRequest builds the eth_sendRawTransaction request broadcasting a signed transaction
+func SendRawTransactionRequest(id uint64, tx *types.Transaction) (*RPCRequest, error) {
+	raw, err := tx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return &RPCRequest{JSONRPC: "2.0", ID: id, Method: "eth_sendRawTransaction", Params: []interface{}{hexutil.Bytes(raw)}}, nil
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToHex(t *testing.T) {
+	assert.Equal(t, "0x0", ToHex(big.NewInt(0)))
+	assert.Equal(t, "0x1", ToHex(big.NewInt(1)))
+	assert.Equal(t, "0xff", ToHex(big.NewInt(255)))
+	assert.Equal(t, "0x100", ToHex(big.NewInt(256)))
+	assert.Equal(t, "-0x1", ToHex(big.NewInt(-1)))
+}
+
+func TestParseDeployments(t *testing.T) {
+	deployments, err := ParseDeployments([]byte(`[{"chainId":1,"router":"0x00000000000000000000000000000000000000aa"}]`))
+	assert.NoError(t, err)
+	deployment, err := deployments.Get(1)
+	assert.NoError(t, err)
+	assert.Equal(t, common.HexToAddress("0xaa"), deployment.Router)
+	_, err = deployments.Get(2)
+	assert.ErrorIs(t, err, ErrNoDeployment)
+}
+
+func TestBuildTransaction(t *testing.T) {
+	key, _ := crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	to := common.HexToAddress("0xaa")
+	params := &MethodParameters{Calldata: []byte{1, 2, 3}, Value: big.NewInt(5)}
+	chainID := big.NewInt(1)
+
+	tx, err := BuildTransaction(to, params, &TxOptions{ChainID: chainID, Nonce: 3, GasLimit: 21000, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(10)})
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(types.DynamicFeeTxType), tx.Type())
+	assert.Equal(t, uint64(3), tx.Nonce())
+	assert.Equal(t, big.NewInt(10), tx.GasFeeCap())
+	assert.Equal(t, &to, tx.To())
+	assert.Equal(t, params.Calldata, tx.Data())
+
+	signed, err := SignTransaction(tx, chainID, key)
+	assert.NoError(t, err)
+	sender, err := types.Sender(types.LatestSignerForChainID(chainID), signed)
+	assert.NoError(t, err)
+	assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), sender)
+
+	req, err := SendRawTransactionRequest(7, signed)
+	assert.NoError(t, err)
+	raw, _ := signed.MarshalBinary()
+	body, err := json.Marshal(req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":7,"method":"eth_sendRawTransaction","params":["`+"0x"+common.Bytes2Hex(raw)+`"]}`, string(body))
+
+	tx, err = BuildTransaction(to, &MethodParameters{Calldata: params.Calldata}, &TxOptions{GasLimit: 21000, GasPrice: big.NewInt(2)})
+	assert.NoError(t, err)
+	assert.Equal(t, uint8(types.LegacyTxType), tx.Type())
+	assert.Equal(t, 0, tx.Value().Sign())
+	signed, err = SignTransaction(tx, chainID, key)
+	assert.NoError(t, err)
+	assert.True(t, signed.Protected())
+
+	_, err = BuildTransaction(to, params, &TxOptions{GasLimit: 21000})
+	assert.ErrorIs(t, err, ErrMissingGasPrice)
+	_, err = BuildTransaction(to, params, &TxOptions{GasFeeCap: big.NewInt(1)})
+	assert.ErrorIs(t, err, ErrMissingChainID)
+	_, err = BuildTransaction(to, params, &TxOptions{ChainID: chainID, GasPrice: big.NewInt(1), GasFeeCap: big.NewInt(1)})
+	assert.ErrorIs(t, err, ErrGasPriceAndCaps)
+}
+
+func TestEthCallRequest(t *testing.T) {
+	from := common.HexToAddress("0xbb")
+	req := EthCallRequest(1, &from, common.HexToAddress("0xaa"), &MethodParameters{Calldata: []byte{0xab}, Value: big.NewInt(255)}, "")
+	body, err := json.Marshal(req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"method":"eth_call","params":[{
+		"from":"0x00000000000000000000000000000000000000bb",
+		"to":"0x00000000000000000000000000000000000000aa",
+		"data":"0xab",
+		"value":"0xff"
+	},"latest"]}`, string(body))
+
+	req = EthCallRequest(2, nil, common.HexToAddress("0xaa"), &MethodParameters{Calldata: []byte{0xab}, Value: big.NewInt(0)}, "0x10")
+	body, err = json.Marshal(req)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":2,"method":"eth_call","params":[{"to":"0x00000000000000000000000000000000000000aa","data":"0xab"},"0x10"]}`, string(body))
+}